@@ -0,0 +1,65 @@
+package text
+
+import (
+	"bytes"
+	"io"
+)
+
+// AppendWrapped wraps the UTF-8 text in src using the rules described above, appends the wrapped text to dst and
+// returns the extended buffer. The wrapped text is written directly into dst (which is grown as append() would grow
+// it), so if dst has enough capacity, the wrapped text is neither copied nor converted to and from a string. The
+// Wrapper does not retain dst or src. If an error occurs, the returned buffer has the wrapped text that was produced
+// before the error appended to dst, as for WrapStringText(). A Wrapper that truncates rows (see
+// ChangeMaximumRowsTo()), applies a bidi mode (see ChangeBidiModeTo()) or detects indents from its input (see
+// ChangeIndentDetectionFromInputTo()) wraps the text as a string first, and then appends it to dst.
+//
+// If src is all printable ASCII and whitespace, and the Wrapper measures text in columns with one of the built-in
+// Measurers, without a WordSegmenter, kinsoku or a word length limit, then src is wrapped by scanning its bytes,
+// without decoding it to runes. This is much faster, and (with enough capacity in dst) does not allocate. The
+// wrapped text is the same either way.
+func (wrapper *Wrapper) AppendWrapped(dst []byte, src []byte) ([]byte, error) {
+	if wrapper.detectIndentsFromInput {
+		return wrapper.AppendWrappedFromAReader(dst, bytes.NewReader(src))
+	}
+
+	if wrapper.wrappedTextMustBeProcessedAsAString() {
+		wrappedText, _, err := wrapper.wrapBytesAndTruncate(src)
+		return append(dst, wrappedText...), err
+	}
+
+	bufferOfWrappedText := bytes.NewBuffer(dst)
+	err := wrapper.wrapBytesInto(bufferOfWrappedText, src)
+
+	return bufferOfWrappedText.Bytes(), err
+}
+
+// AppendWrappedFromAReader is the same as AppendWrapped(), but reads the UTF-8 text to wrap from reader.
+func (wrapper *Wrapper) AppendWrappedFromAReader(dst []byte, reader io.Reader) ([]byte, error) {
+	if wrapper.wrappedTextMustBeProcessedAsAString() {
+		wrappedText, err := wrapper.WrapUTF8TextFromAReader(reader)
+		return append(dst, wrappedText...), err
+	}
+
+	reader = wrapper.normalizingReaderFor(wrapper.inputLimitingReaderFor(reader))
+
+	bufferOfWrappedText := bytes.NewBuffer(dst)
+	err := wrapper.wrapFromReaderInto(bufferOfWrappedText, reader)
+
+	return bufferOfWrappedText.Bytes(), err
+}
+
+// MustAppendWrapped is the same as AppendWrapped() but panics if an error occurs
+func (wrapper *Wrapper) MustAppendWrapped(dst []byte, src []byte) []byte {
+	dst, err := wrapper.AppendWrapped(dst, src)
+	if err != nil {
+		panic(err)
+	}
+
+	return dst
+}
+
+// wrappedTextMustBeProcessedAsAString returns true if the wrapped text is changed after it is wrapped (by truncation
+// or bidi layout), or if the input is rearranged before it is wrapped (by indent detection).
+func (wrapper *Wrapper) wrappedTextMustBeProcessedAsAString() bool {
+	return wrapper.maximumRows > 0 || wrapper.bidiMode != BidiDisabled || wrapper.detectIndentsFromInput
+}
@@ -0,0 +1,164 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type AppendWrappedTestCase struct {
+	testName            string
+	wrapper             *text.Wrapper
+	dst                 string
+	src                 string
+	expectedResult      string
+	expectLimitExceeded bool
+}
+
+func (testCase *AppendWrappedTestCase) RunTest() error {
+	result, err := testCase.wrapper.AppendWrapped([]byte(testCase.dst), []byte(testCase.src))
+
+	if testCase.expectLimitExceeded {
+		if !errors.Is(err, text.ErrLimitExceeded) {
+			return fmt.Errorf("[%s] expected ErrLimitExceeded, got = (%v)", testCase.testName, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if string(result) != testCase.expectedResult {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedResult, string(result))
+	}
+
+	wrappedString, _ := testCase.wrapper.WrapStringText(testCase.src)
+	if string(result) != testCase.dst+wrappedString {
+		return fmt.Errorf("[%s] expected the same text as WrapStringText() = (%q), got = (%q)", testCase.testName, testCase.dst+wrappedString, string(result))
+	}
+
+	return nil
+}
+
+func TestAppendWrapped(t *testing.T) {
+	testCases := []*AppendWrappedTestCase{
+		{
+			testName:       "empty dst and src",
+			wrapper:        text.NewWrapper(),
+			dst:            "",
+			src:            "",
+			expectedResult: "",
+		},
+		{
+			testName:       "empty src",
+			wrapper:        text.NewWrapper(),
+			dst:            "before",
+			src:            "  \n ",
+			expectedResult: "before",
+		},
+		{
+			testName:       "appended to empty dst",
+			wrapper:        text.NewWrapper().UsingRowWidth(8),
+			dst:            "",
+			src:            "one two three",
+			expectedResult: "one two\nthree",
+		},
+		{
+			testName:       "appended to existing text",
+			wrapper:        text.NewWrapper().UsingRowWidth(8),
+			dst:            "before\n",
+			src:            "one two three",
+			expectedResult: "before\none two\nthree",
+		},
+		{
+			testName:       "with indents",
+			wrapper:        text.NewWrapper().UsingRowWidth(8).UsingIndentStringForFirstRow("* ").UsingIndentStringForRowsAfterTheFirst("  "),
+			dst:            "> ",
+			src:            "one two three",
+			expectedResult: "> * one\n  two\n  three",
+		},
+		{
+			testName:       "word longer than a row",
+			wrapper:        text.NewWrapper().UsingRowWidth(4),
+			dst:            "|",
+			src:            "abcdefghij",
+			expectedResult: "|abcd\nefgh\nij",
+		},
+		{
+			testName:       "multi-byte text",
+			wrapper:        text.NewWrapper().UsingRowWidth(5),
+			dst:            "日",
+			src:            "ÀÉÎÕÜ çñø",
+			expectedResult: "日ÀÉÎÕÜ\nçñø",
+		},
+		{
+			testName:       "invalid bytes passed through",
+			wrapper:        text.NewWrapper().UsingRowWidth(4).UsingInvalidUTF8Policy(text.PassInvalidUTF8Through),
+			dst:            "x",
+			src:            "ab\xffc de",
+			expectedResult: "xab\xffc\nde",
+		},
+		{
+			testName:       "truncated rows",
+			wrapper:        text.NewWrapper().UsingRowWidth(8).UsingMaximumRows(1),
+			dst:            "before\n",
+			src:            "one two three",
+			expectedResult: "before\none two…",
+		},
+		{
+			testName:       "indents detected from input",
+			wrapper:        text.NewWrapper().UsingRowWidth(12).UsingIndentsDetectedFromInput(),
+			dst:            "before\n",
+			src:            "  -a  one two\n      three",
+			expectedResult: "before\n  -a  one\n      two\n      three",
+		},
+		{
+			testName:            "error leaves dst and the text before the error",
+			wrapper:             text.NewWrapper().UsingRowWidth(8).UsingOutputRowLimit(2),
+			dst:                 "before\n",
+			src:                 "one two three four five",
+			expectedResult:      "before\none two\nthree",
+			expectLimitExceeded: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestAppendWrappedUsesTheCapacityOfDst(t *testing.T) {
+	wrapper := text.NewWrapper().UsingRowWidth(8)
+
+	dst := make([]byte, 0, 64)
+	dst = append(dst, "before\n"...)
+
+	result, err := wrapper.AppendWrapped(dst, []byte("one two three"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(result) != "before\none two\nthree" {
+		t.Errorf("expected = (%q), got = (%q)", "before\none two\nthree", string(result))
+	}
+
+	if &result[0] != &dst[0] {
+		t.Errorf("expected the wrapped text to be appended in the capacity of dst, but a new buffer was allocated")
+	}
+}
+
+func TestAppendWrappedFromAReader(t *testing.T) {
+	wrapper := text.NewWrapper().UsingRowWidth(8)
+
+	result, err := wrapper.AppendWrappedFromAReader([]byte("before\n"), strings.NewReader("one two three"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(result) != "before\none two\nthree" {
+		t.Errorf("expected = (%q), got = (%q)", "before\none two\nthree", string(result))
+	}
+}
@@ -0,0 +1,220 @@
+package text
+
+import "bytes"
+
+// wrapBytesInto appends the text wrapped from src to bufferOfWrappedText. If the Wrapper's configuration allows it
+// (see asciiPathIsAvailable()) and src is ASCII that is wrapped byte by byte (see textIsPrintableASCIIOrWhitespace()),
+// src is wrapped by wrapASCIIInto(). Otherwise, it is wrapped by wrapFromReaderInto().
+func (wrapper *Wrapper) wrapBytesInto(bufferOfWrappedText *bytes.Buffer, src []byte) error {
+	if wrapper.asciiPathIsAvailable() && (wrapper.inputByteLimit == 0 || uint(len(src)) <= wrapper.inputByteLimit) && textIsPrintableASCIIOrWhitespace(src) {
+		return wrapper.wrapASCIIInto(bufferOfWrappedText, src)
+	}
+
+	reader := wrapper.normalizingReaderFor(wrapper.inputLimitingReaderFor(bytes.NewReader(src)))
+	return wrapper.wrapFromReaderInto(bufferOfWrappedText, reader)
+}
+
+// asciiPathIsAvailable returns true if the Wrapper's configuration wraps printable ASCII and whitespace the same way
+// for every input: each byte is one column wide, words are broken only at whitespace, and no character moves a row
+// break. ASCII is the same in every Unicode normalization form, so the InputNormalization does not matter.
+func (wrapper *Wrapper) asciiPathIsAvailable() bool {
+	return wrapper.fontMetrics == nil &&
+		(wrapper.measurer == RuneCountMeasurer || wrapper.measurer == EastAsianNarrowMeasurer || wrapper.measurer == EastAsianWideMeasurer) &&
+		wrapper.wordSegmenter == nil &&
+		wrapper.kinsokuAdjustment == KinsokuDisabled &&
+		wrapper.wordLengthLimit == 0 &&
+		!wrapper.detectIndentsFromInput
+}
+
+// textIsPrintableASCIIOrWhitespace returns true if every byte in src is a printable ASCII character (U+0020 through
+// U+007E) or ASCII whitespace. Other ASCII control characters are excluded, because some Measurers give them no
+// width.
+func textIsPrintableASCIIOrWhitespace(src []byte) bool {
+	for _, b := range src {
+		if (b < 0x20 || b > 0x7e) && !isASCIIWhitespace(b) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isASCIIWhitespace returns true for the ASCII bytes for which unicode.IsSpace() is true.
+func isASCIIWhitespace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+
+	return false
+}
+
+// wrapASCIIInto is the same as wrapFromReaderInto(), for input that is printable ASCII or whitespace, when
+// asciiPathIsAvailable() is true. Every byte is a rune that is one column wide, so the wrapped rows are found by
+// scanning src, and words are copied from src into bufferOfWrappedText. It makes the same choices as
+// wrapFromReaderInto(), and so returns the same wrapped text and errors.
+func (wrapper *Wrapper) wrapASCIIInto(bufferOfWrappedText *bytes.Buffer, src []byte) (err error) {
+	numberOfWrappedRows := 1
+
+	// if wrapping fails, the wrapped text ends with the last word that was written
+	positionAfterTheLastWrittenWord := 0
+	lengthOfWrappedTextAfterTheLastWrittenWord := bufferOfWrappedText.Len()
+	numberOfRowsAfterTheLastWrittenWord := 1
+	defer func() {
+		if err != nil {
+			bufferOfWrappedText.Truncate(lengthOfWrappedTextAfterTheLastWrittenWord)
+			err = &WrapError{
+				Err:        err,
+				ByteOffset: int64(positionAfterTheLastWrittenWord),
+				RuneOffset: int64(positionAfterTheLastWrittenWord),
+				Line:       1 + bytes.Count(src[:positionAfterTheLastWrittenWord], []byte{'\n'}),
+				Row:        numberOfRowsAfterTheLastWrittenWord,
+			}
+		}
+	}()
+
+	indexOfNextByte := indexOfFirstNonWhitespaceByteIn(src, 0)
+	if indexOfNextByte == len(src) {
+		return nil
+	}
+
+	if err := writeRunesInto(bufferOfWrappedText, wrapper.initialLineIndentString); err != nil {
+		return err
+	}
+
+	columnsRemainingInCurrentWrappedLine := int(wrapper.columnsPerRow) - widthOfIndentMeasuredBy(wrapper.measurer, wrapper.initialLineIndentString)
+	columnsInAWrappedLineAfterTheIndent := int(wrapper.columnsPerRow) - widthOfIndentMeasuredBy(wrapper.measurer, wrapper.subsequentLinesIndentString)
+
+	whitespaceColumns := 0
+	atTheStartOfALine := true
+	breakIsAllowedBeforeNextWord := false
+
+	for {
+		if indexOfNextByte == len(src) {
+			return nil
+		}
+
+		startOfWord := indexOfNextByte
+		endOfWord, wordIsCutByTheEndOfTheLine := endOfASCIIWordThatFitsIn(src, startOfWord, columnsRemainingInCurrentWrappedLine-whitespaceColumns, atTheStartOfALine)
+		indexOfNextByte = endOfWord
+
+		if wordIsCutByTheEndOfTheLine {
+			// if there was no whitespace before this word in this line, then this word is at least as long as an
+			// entire line
+			if !breakIsAllowedBeforeNextWord {
+				if _, err := bufferOfWrappedText.Write(src[startOfWord:endOfWord]); err != nil {
+					return err
+				}
+
+				positionAfterTheLastWrittenWord = endOfWord
+				lengthOfWrappedTextAfterTheLastWrittenWord = bufferOfWrappedText.Len()
+				numberOfRowsAfterTheLastWrittenWord = numberOfWrappedRows
+
+				if err := wrapper.insertLineBreakAndIndentInto(bufferOfWrappedText, &numberOfWrappedRows); err != nil {
+					return err
+				}
+
+				columnsRemainingInCurrentWrappedLine = columnsInAWrappedLineAfterTheIndent
+				atTheStartOfALine = true
+				breakIsAllowedBeforeNextWord = false
+			} else {
+				// only a fragment of the word fits, but the word can move to the next line
+				if err := wrapper.insertLineBreakAndIndentInto(bufferOfWrappedText, &numberOfWrappedRows); err != nil {
+					return err
+				}
+
+				if _, err := bufferOfWrappedText.Write(src[startOfWord:endOfWord]); err != nil {
+					return err
+				}
+
+				positionAfterTheLastWrittenWord = endOfWord
+				lengthOfWrappedTextAfterTheLastWrittenWord = bufferOfWrappedText.Len()
+				numberOfRowsAfterTheLastWrittenWord = numberOfWrappedRows
+
+				columnsRemainingInCurrentWrappedLine = columnsInAWrappedLineAfterTheIndent - (endOfWord - startOfWord)
+				whitespaceColumns = 0
+				atTheStartOfALine = endOfWord == startOfWord
+				breakIsAllowedBeforeNextWord = false
+			}
+		} else {
+			for ; whitespaceColumns > 0; whitespaceColumns-- {
+				if err := bufferOfWrappedText.WriteByte(' '); err != nil {
+					return err
+				}
+
+				columnsRemainingInCurrentWrappedLine--
+			}
+
+			if _, err := bufferOfWrappedText.Write(src[startOfWord:endOfWord]); err != nil {
+				return err
+			}
+
+			positionAfterTheLastWrittenWord = endOfWord
+			lengthOfWrappedTextAfterTheLastWrittenWord = bufferOfWrappedText.Len()
+			numberOfRowsAfterTheLastWrittenWord = numberOfWrappedRows
+
+			columnsRemainingInCurrentWrappedLine -= endOfWord - startOfWord
+			atTheStartOfALine = false
+			breakIsAllowedBeforeNextWord = true
+		}
+
+		if !atTheStartOfALine {
+			for whitespaceColumns < columnsRemainingInCurrentWrappedLine && indexOfNextByte < len(src) && isASCIIWhitespace(src[indexOfNextByte]) {
+				whitespaceColumns++
+				indexOfNextByte++
+			}
+
+			if indexOfNextByte == len(src) {
+				return nil
+			}
+
+			// whitespace continues to end of wrappable line, so wrap and don't write accumulated whitespace
+			if whitespaceColumns >= columnsRemainingInCurrentWrappedLine {
+				if indexOfNextByte = indexOfFirstNonWhitespaceByteIn(src, indexOfNextByte); indexOfNextByte == len(src) {
+					return nil
+				}
+
+				if err := wrapper.insertLineBreakAndIndentInto(bufferOfWrappedText, &numberOfWrappedRows); err != nil {
+					return err
+				}
+
+				columnsRemainingInCurrentWrappedLine = columnsInAWrappedLineAfterTheIndent
+				whitespaceColumns = 0
+				atTheStartOfALine = true
+				breakIsAllowedBeforeNextWord = false
+			}
+		}
+	}
+}
+
+// endOfASCIIWordThatFitsIn is the same as readWordCharactersThatFitIn(), for the ASCII word that starts at
+// startOfWord in src. It returns the index of the byte after the last byte of the word that fits.
+func endOfASCIIWordThatFitsIn(src []byte, startOfWord int, columnsAvailable int, atTheStartOfALine bool) (endOfWord int, wordIsCutByTheEndOfTheLine bool) {
+	for endOfWord = startOfWord; endOfWord < len(src) && !isASCIIWhitespace(src[endOfWord]); endOfWord++ {
+		if endOfWord-startOfWord+1 > columnsAvailable && !(atTheStartOfALine && endOfWord == startOfWord) {
+			return endOfWord, true
+		}
+	}
+
+	return endOfWord, false
+}
+
+func indexOfFirstNonWhitespaceByteIn(src []byte, startingIndex int) int {
+	for startingIndex < len(src) && isASCIIWhitespace(src[startingIndex]) {
+		startingIndex++
+	}
+
+	return startingIndex
+}
+
+// widthOfIndentMeasuredBy is the same as widthOfRunesUsing() with a measurerAdvancer, but does not allocate the
+// runeAdvancer.
+func widthOfIndentMeasuredBy(measurer Measurer, indent []rune) int {
+	advancer := measurerAdvancer{measurer: measurer}
+	widthOfIndent := 0
+	for _, r := range indent {
+		widthOfIndent += advancer.advanceOf(noPreviousRune, r)
+	}
+
+	return widthOfIndent
+}
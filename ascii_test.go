@@ -0,0 +1,172 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+// oneColumnMeasurer measures every rune the way the built-in Measurers measure printable ASCII, but it is not one
+// of the built-in Measurers, so a Wrapper that uses it does not use the ASCII path.
+var oneColumnMeasurer = text.WidthFunc(func(r rune) int { return 1 })
+
+type ASCIIPathTestCase struct {
+	testName             string
+	rowWidth             uint
+	firstRowIndent       string
+	rowsAfterFirstIndent string
+	outputRowLimit       uint
+	unwrappedString      string
+}
+
+func (testCase *ASCIIPathTestCase) wrapperUsing(measurer text.Measurer) *text.Wrapper {
+	return text.NewWrapper().UsingRowWidth(testCase.rowWidth).
+		UsingIndentStringForFirstRow(testCase.firstRowIndent).
+		UsingIndentStringForRowsAfterTheFirst(testCase.rowsAfterFirstIndent).
+		UsingOutputRowLimit(testCase.outputRowLimit).
+		UsingMeasurer(measurer)
+}
+
+func (testCase *ASCIIPathTestCase) RunTest() error {
+	expectedWrappedString, expectedErr := testCase.wrapperUsing(oneColumnMeasurer).WrapStringText(testCase.unwrappedString)
+
+	for _, measurer := range []text.Measurer{text.RuneCountMeasurer, text.EastAsianNarrowMeasurer, text.EastAsianWideMeasurer} {
+		wrapper := testCase.wrapperUsing(measurer)
+
+		wrappedString, err := wrapper.WrapStringText(testCase.unwrappedString)
+		if err := errorUnlessTheSameWrapResult(expectedWrappedString, expectedErr, wrappedString, err); err != nil {
+			return fmt.Errorf("[%s] (WrapStringText) %s", testCase.testName, err)
+		}
+
+		wrappedBytes, err := wrapper.AppendWrapped(nil, []byte(testCase.unwrappedString))
+		if err := errorUnlessTheSameWrapResult(expectedWrappedString, expectedErr, string(wrappedBytes), err); err != nil {
+			return fmt.Errorf("[%s] (AppendWrapped) %s", testCase.testName, err)
+		}
+	}
+
+	return nil
+}
+
+func errorUnlessTheSameWrapResult(expectedWrappedString string, expectedErr error, wrappedString string, err error) error {
+	if wrappedString != expectedWrappedString {
+		return fmt.Errorf("expected = (%q), got = (%q)", expectedWrappedString, wrappedString)
+	}
+
+	if (expectedErr == nil) != (err == nil) {
+		return fmt.Errorf("expected error = (%v), got = (%v)", expectedErr, err)
+	}
+
+	if expectedErr != nil {
+		var expectedWrapError, wrapError *text.WrapError
+		if !errors.As(expectedErr, &expectedWrapError) || !errors.As(err, &wrapError) {
+			return fmt.Errorf("expected a WrapError, got = (%v) and (%v)", expectedErr, err)
+		}
+
+		if wrapError.Error() != expectedWrapError.Error() || wrapError.ByteOffset != expectedWrapError.ByteOffset || wrapError.RuneOffset != expectedWrapError.RuneOffset ||
+			wrapError.Line != expectedWrapError.Line || wrapError.Row != expectedWrapError.Row {
+			return fmt.Errorf("expected error = (%+v), got = (%+v)", *expectedWrapError, *wrapError)
+		}
+	}
+
+	return nil
+}
+
+func TestASCIIPathMatchesTheGeneralPath(t *testing.T) {
+	testCases := []*ASCIIPathTestCase{
+		{
+			testName:        "empty string",
+			rowWidth:        10,
+			unwrappedString: "",
+		},
+		{
+			testName:        "only whitespace",
+			rowWidth:        10,
+			unwrappedString: " \t\r\n\v\f ",
+		},
+		{
+			testName:        "words that fit",
+			rowWidth:        10,
+			unwrappedString: "one two three four five six",
+		},
+		{
+			testName:        "mixed whitespace",
+			rowWidth:        10,
+			unwrappedString: "  one\ttwo\r\nthree \n\n four   five\fsix\v ",
+		},
+		{
+			testName:        "words longer than a row",
+			rowWidth:        4,
+			unwrappedString: "abcdefghij ab abcdefg a",
+		},
+		{
+			testName:        "words that exactly fill rows",
+			rowWidth:        5,
+			unwrappedString: "abcde fghij k lmnop",
+		},
+		{
+			testName:             "indents",
+			rowWidth:             10,
+			firstRowIndent:       "* ",
+			rowsAfterFirstIndent: "  ",
+			unwrappedString:      "one two three four five sixteen seventeen",
+		},
+		{
+			testName:        "rows over the row limit",
+			rowWidth:        8,
+			outputRowLimit:  2,
+			unwrappedString: "one two\nthree four five",
+		},
+		{
+			testName:        "chopped word over the row limit",
+			rowWidth:        4,
+			outputRowLimit:  2,
+			unwrappedString: "ab\ncd abcdefghij",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestASCIIPathMatchesTheGeneralPathForGeneratedText(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	whitespace := []string{" ", " ", " ", "  ", "\t", "\n", "\r\n", " \n ", "\v", "\f"}
+
+	for testNumber := 0; testNumber < 500; testNumber++ {
+		var textBuilder strings.Builder
+		for numberOfWords := random.Intn(30); numberOfWords > 0; numberOfWords-- {
+			if random.Intn(4) == 0 {
+				textBuilder.WriteString(whitespace[random.Intn(len(whitespace))])
+			}
+
+			for wordLength := 1 + random.Intn(14); wordLength > 0; wordLength-- {
+				textBuilder.WriteByte(byte(0x21 + random.Intn(0x7e-0x21+1)))
+			}
+
+			textBuilder.WriteString(whitespace[random.Intn(len(whitespace))])
+		}
+
+		testCase := &ASCIIPathTestCase{
+			testName:        fmt.Sprintf("generated text %d", testNumber),
+			rowWidth:        uint(3 + random.Intn(20)),
+			outputRowLimit:  uint(random.Intn(4)),
+			unwrappedString: textBuilder.String(),
+		}
+
+		if random.Intn(2) == 0 {
+			testCase.firstRowIndent = strings.Repeat(" ", random.Intn(3))
+			testCase.rowsAfterFirstIndent = strings.Repeat("-", random.Intn(3))
+		}
+
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err)
+		}
+	}
+}
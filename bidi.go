@@ -0,0 +1,359 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// BidiMode determines how a Wrapper handles text that contains right-to-left scripts, such as Arabic and Hebrew.
+type BidiMode int
+
+const (
+	// BidiDisabled treats all text as left-to-right. This is the default.
+	BidiDisabled BidiMode = iota
+
+	// BidiLogicalOrder determines the direction of the paragraph (see ChangeParagraphDirectionTo()). The rows of a
+	// right-to-left paragraph are aligned (by default, to the right) and have their indent strings on the right.
+	// The text of each row is left in logical order, for terminals that reorder the characters of each row
+	// themselves using a left-to-right base direction.
+	BidiLogicalOrder
+
+	// BidiVisualOrder is the same as BidiLogicalOrder, except that the text of each row (in paragraphs of either
+	// direction) is also reordered into the order in which it is displayed from left to right, using the Unicode
+	// Bidirectional Algorithm (Unicode Standard Annex #9), for terminals that do not reorder text.
+	BidiVisualOrder
+)
+
+// ParagraphDirection is the base direction of a paragraph of text.
+type ParagraphDirection int
+
+const (
+	// DetectParagraphDirection uses the direction of the first strong (that is, left-to-right or right-to-left)
+	// character in the text, as described in rules P2 and P3 of the Unicode Bidirectional Algorithm. If the text
+	// has no strong characters, the paragraph is left-to-right.
+	DetectParagraphDirection ParagraphDirection = iota
+
+	// LeftToRightParagraph is a paragraph with a left-to-right base direction.
+	LeftToRightParagraph
+
+	// RightToLeftParagraph is a paragraph with a right-to-left base direction.
+	RightToLeftParagraph
+)
+
+// ChangeBidiModeTo changes how the Wrapper handles bidirectional text. Lines are always broken in logical order
+// (as required by the Unicode Bidirectional Algorithm), and the mode is applied to the wrapped rows, after any
+// truncation. The default is BidiDisabled.
+func (wrapper *Wrapper) ChangeBidiModeTo(mode BidiMode) *Wrapper {
+	wrapper.bidiMode = mode
+	return wrapper
+}
+
+// UsingBidiMode is the same as ChangeBidiModeTo(), but provides a more readable name if this is chained with
+// the constructor, as in:
+//    wrapper := text.NewWrapper().UsingBidiMode(text.BidiVisualOrder).UsingRowWidth(40)
+func (wrapper *Wrapper) UsingBidiMode(mode BidiMode) *Wrapper {
+	return wrapper.ChangeBidiModeTo(mode)
+}
+
+// ChangeParagraphDirectionTo sets the base direction of the wrapped text, when the bidi mode is not BidiDisabled.
+// The default is DetectParagraphDirection, which uses the wrapped text (without indent strings) to determine the
+// direction.
+func (wrapper *Wrapper) ChangeParagraphDirectionTo(direction ParagraphDirection) *Wrapper {
+	wrapper.paragraphDirection = direction
+	return wrapper
+}
+
+// UsingParagraphDirection is the same as ChangeParagraphDirectionTo(), but provides a more readable name if this
+// is chained with the constructor.
+func (wrapper *Wrapper) UsingParagraphDirection(direction ParagraphDirection) *Wrapper {
+	return wrapper.ChangeParagraphDirectionTo(direction)
+}
+
+// ChangeRightToLeftAlignmentTo sets the alignment of the rows of a right-to-left paragraph, when the bidi mode is
+// not BidiDisabled. With AlignRight (the default), each row is padded with spaces on the left to the row width. With
+// AlignCenter, it is padded on both sides. With AlignLeft, it is not padded.
+func (wrapper *Wrapper) ChangeRightToLeftAlignmentTo(alignment Alignment) *Wrapper {
+	wrapper.rightToLeftAlignment = alignment
+	return wrapper
+}
+
+// UsingRightToLeftAlignment is the same as ChangeRightToLeftAlignmentTo(), but provides a more readable name if
+// this is chained with the constructor.
+func (wrapper *Wrapper) UsingRightToLeftAlignment(alignment Alignment) *Wrapper {
+	return wrapper.ChangeRightToLeftAlignmentTo(alignment)
+}
+
+// ParagraphDirectionOf returns the direction of the first strong character in s that is not inside an isolate,
+// as described in rules P2 and P3 of the Unicode Bidirectional Algorithm. It returns RightToLeftParagraph if that
+// character is right-to-left, and LeftToRightParagraph otherwise (including when s has no strong characters).
+func ParagraphDirectionOf(s string) ParagraphDirection {
+	return paragraphDirectionOfRunes([]rune(s))
+}
+
+func paragraphDirectionOfRunes(runes []rune) ParagraphDirection {
+	depthOfIsolates := 0
+
+	for _, r := range runes {
+		properties, _ := bidi.LookupRune(r)
+
+		switch properties.Class() {
+		case bidi.LRI, bidi.RLI, bidi.FSI:
+			depthOfIsolates++
+		case bidi.PDI:
+			if depthOfIsolates > 0 {
+				depthOfIsolates--
+			}
+		case bidi.L:
+			if depthOfIsolates == 0 {
+				return LeftToRightParagraph
+			}
+		case bidi.R, bidi.AL:
+			if depthOfIsolates == 0 {
+				return RightToLeftParagraph
+			}
+		}
+	}
+
+	return LeftToRightParagraph
+}
+
+// laidOutForBidi applies the bidi mode to wrapped text.
+func (wrapper *Wrapper) laidOutForBidi(wrappedText string) (string, error) {
+	if wrapper.bidiMode == BidiDisabled || wrappedText == "" {
+		return wrappedText, nil
+	}
+
+	wrappedRows := strings.Split(wrappedText, wrapper.lineBreakSequence)
+	textOfRows := make([][]rune, len(wrappedRows))
+	for rowIndex, row := range wrappedRows {
+		textOfRows[rowIndex] = []rune(row)[len(wrapper.indentStringForRow(rowIndex)):]
+	}
+
+	direction := wrapper.paragraphDirection
+	if direction == DetectParagraphDirection {
+		direction = paragraphDirectionOfRunes([]rune(strings.Join(stringsOfRunes(textOfRows), " ")))
+	}
+
+	if wrapper.bidiMode == BidiVisualOrder {
+		levelsOfRows, err := embeddingLevelsOfRows(textOfRows, direction)
+		if err != nil {
+			return "", err
+		}
+
+		for rowIndex := range textOfRows {
+			textOfRows[rowIndex] = runesInVisualOrder(textOfRows[rowIndex], levelsOfRows[rowIndex], direction)
+		}
+	}
+
+	for rowIndex, rowText := range textOfRows {
+		indent := wrapper.indentStringForRow(rowIndex)
+
+		if direction == LeftToRightParagraph {
+			wrappedRows[rowIndex] = string(indent) + string(rowText)
+			continue
+		}
+
+		rowForDisplay := string(rowText) + bidi.ReverseString(string(indent))
+		if wrapper.rightToLeftAlignment != AlignLeft {
+			rowForDisplay = padUsing(wrapper.advancer(), rowForDisplay, wrapper.rowWidthInWidthUnits(), wrapper.rightToLeftAlignment)
+		}
+
+		wrappedRows[rowIndex] = rowForDisplay
+	}
+
+	return strings.Join(wrappedRows, wrapper.lineBreakSequence), nil
+}
+
+func stringsOfRunes(runesOfStrings [][]rune) []string {
+	strings := make([]string, len(runesOfStrings))
+	for i, runes := range runesOfStrings {
+		strings[i] = string(runes)
+	}
+
+	return strings
+}
+
+// embeddingLevelsOfRows resolves the embedding level of each rune in the rows, which are treated as lines of a
+// single paragraph with the provided direction (so that, for example, a number at the start of a row takes its
+// level from the strong character at the end of the row before it). The levels are resolved with
+// golang.org/x/text/unicode/bidi, which reports only whether each rune is left-to-right or right-to-left. The
+// numeric level is recovered from that: without explicit embeddings, a left-to-right rune is at level 2 if it is
+// in a right-to-left paragraph or is part of a number that follows right-to-left text (rules W2, W7 and I1), and is
+// at level 0 otherwise, and a right-to-left rune is at level 1.
+func embeddingLevelsOfRows(textOfRows [][]rune, direction ParagraphDirection) ([][]int, error) {
+	paragraphRunes := make([]rune, 0, 80*len(textOfRows))
+	startOfRows := make([]int, len(textOfRows))
+
+	var options []bidi.Option
+	if direction == RightToLeftParagraph {
+		options = append(options, bidi.DefaultDirection(bidi.RightToLeft))
+	} else {
+		// x/text/unicode/bidi detects the direction unless it is right-to-left, so a LEFT-TO-RIGHT MARK forces it
+		paragraphRunes = append(paragraphRunes, '\u200e')
+	}
+
+	for rowIndex, rowText := range textOfRows {
+		if rowIndex > 0 {
+			paragraphRunes = append(paragraphRunes, ' ')
+		}
+
+		startOfRows[rowIndex] = len(paragraphRunes)
+		paragraphRunes = append(paragraphRunes, rowText...)
+	}
+
+	var paragraph bidi.Paragraph
+	if _, err := paragraph.SetString(string(paragraphRunes), options...); err != nil {
+		return nil, err
+	}
+
+	ordering, err := paragraph.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]int, len(paragraphRunes))
+	for runIndex := 0; runIndex < ordering.NumRuns(); runIndex++ {
+		run := ordering.Run(runIndex)
+		if run.Direction() != bidi.RightToLeft {
+			continue
+		}
+
+		runStart, runEnd := run.Pos()
+		for runeIndex := runStart; runeIndex <= runEnd; runeIndex++ {
+			levels[runeIndex] = 1
+		}
+	}
+
+	raiseLeftToRightLevels(paragraphRunes, levels, direction)
+
+	levelsOfRows := make([][]int, len(textOfRows))
+	for rowIndex, rowText := range textOfRows {
+		levelsOfRows[rowIndex] = levels[startOfRows[rowIndex] : startOfRows[rowIndex]+len(rowText)]
+	}
+
+	return levelsOfRows, nil
+}
+
+// raiseLeftToRightLevels changes the level of left-to-right runes from 0 to 2 where rules W2, W7 and I1 require
+// it: every left-to-right rune in a right-to-left paragraph, and, in a left-to-right paragraph, European numbers
+// whose last preceding strong character is right-to-left, Arabic numbers, and the separators and terminators that
+// rules W4 and W5 join to them.
+func raiseLeftToRightLevels(runes []rune, levels []int, direction ParagraphDirection) {
+	if direction == RightToLeftParagraph {
+		for runeIndex := range levels {
+			if levels[runeIndex] == 0 {
+				levels[runeIndex] = 2
+			}
+		}
+
+		return
+	}
+
+	lastStrongIsRightToLeft := false
+	isANumber := make([]bool, len(runes))
+
+	for runeIndex, r := range runes {
+		properties, _ := bidi.LookupRune(r)
+
+		switch properties.Class() {
+		case bidi.L:
+			lastStrongIsRightToLeft = false
+		case bidi.R, bidi.AL:
+			lastStrongIsRightToLeft = true
+		case bidi.EN:
+			isANumber[runeIndex] = lastStrongIsRightToLeft
+		case bidi.AN:
+			isANumber[runeIndex] = true
+		}
+	}
+
+	for runeIndex, r := range runes {
+		if isANumber[runeIndex] || levels[runeIndex] != 0 {
+			continue
+		}
+
+		properties, _ := bidi.LookupRune(r)
+		switch properties.Class() {
+		case bidi.ES, bidi.CS:
+			isANumber[runeIndex] = runeIndex > 0 && runeIndex+1 < len(runes) && isANumber[runeIndex-1] && isANumber[runeIndex+1]
+		case bidi.ET, bidi.NSM:
+			isANumber[runeIndex] = (runeIndex > 0 && isANumber[runeIndex-1]) || (runeIndex+1 < len(runes) && isANumber[runeIndex+1])
+		}
+	}
+
+	for runeIndex := range levels {
+		if isANumber[runeIndex] && levels[runeIndex] == 0 {
+			levels[runeIndex] = 2
+		}
+	}
+}
+
+// runesInVisualOrder reorders a row from logical order to the order in which it is displayed from left to right,
+// using rules L1 to L4 of the Unicode Bidirectional Algorithm: whitespace at the end of the row is reset to the
+// paragraph level, then each maximal sequence of characters at or above each odd level is reversed, from the
+// highest level down. A character and the combining marks that follow it are kept together and in order, and
+// brackets in right-to-left text are mirrored.
+func runesInVisualOrder(rowText []rune, levels []int, direction ParagraphDirection) []rune {
+	paragraphLevel := 0
+	if direction == RightToLeftParagraph {
+		paragraphLevel = 1
+	}
+
+	type characterWithLevel struct {
+		runes []rune
+		level int
+	}
+
+	characters := make([]characterWithLevel, 0, len(rowText))
+	highestLevel := paragraphLevel
+
+	for runeIndex, r := range rowText {
+		if len(characters) > 0 && runeExtendsPreviousCharacter(r) {
+			characters[len(characters)-1].runes = append(characters[len(characters)-1].runes, r)
+			continue
+		}
+
+		characters = append(characters, characterWithLevel{runes: []rune{r}, level: levels[runeIndex]})
+		if levels[runeIndex] > highestLevel {
+			highestLevel = levels[runeIndex]
+		}
+	}
+
+	for characterIndex := len(characters) - 1; characterIndex >= 0 && unicode.IsSpace(characters[characterIndex].runes[0]); characterIndex-- {
+		characters[characterIndex].level = paragraphLevel
+	}
+
+	for level := highestLevel; level >= 1; level-- {
+		for startIndex := 0; startIndex < len(characters); {
+			if characters[startIndex].level < level {
+				startIndex++
+				continue
+			}
+
+			endIndex := startIndex
+			for endIndex < len(characters) && characters[endIndex].level >= level {
+				endIndex++
+			}
+
+			for i, j := startIndex, endIndex-1; i < j; i, j = i+1, j-1 {
+				characters[i], characters[j] = characters[j], characters[i]
+			}
+
+			startIndex = endIndex
+		}
+	}
+
+	visualRunes := make([]rune, 0, len(rowText))
+	for _, character := range characters {
+		if character.level%2 == 1 {
+			visualRunes = append(visualRunes, []rune(bidi.ReverseString(string(character.runes[:1])))...)
+			visualRunes = append(visualRunes, character.runes[1:]...)
+		} else {
+			visualRunes = append(visualRunes, character.runes...)
+		}
+	}
+
+	return visualRunes
+}
@@ -0,0 +1,124 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type BidiTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	unwrappedString       string
+	expectedWrappedString string
+}
+
+func (testCase *BidiTestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestParagraphDirectionOf(t *testing.T) {
+	for testString, expectedDirection := range map[string]text.ParagraphDirection{
+		"":                          text.LeftToRightParagraph,
+		"123 ...":                   text.LeftToRightParagraph,
+		"hello שלום":                text.LeftToRightParagraph,
+		"שלום hello":                text.RightToLeftParagraph,
+		"123 مرحبا":                 text.RightToLeftParagraph,
+		"\u2067hello\u2069 שלום":    text.RightToLeftParagraph,
+		"\u2068שלום\u2069 hello":    text.LeftToRightParagraph,
+		"\u2067\u2066a\u2069\u2069": text.LeftToRightParagraph,
+	} {
+		if direction := text.ParagraphDirectionOf(testString); direction != expectedDirection {
+			t.Errorf("for (%q) expected direction = (%d), got = (%d)", testString, expectedDirection, direction)
+		}
+	}
+}
+
+func TestWrapBidiText(t *testing.T) {
+	testCases := []*BidiTestCase{
+		{
+			testName:              "bidi disabled leaves right-to-left text as is",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* "),
+			unwrappedString:       "שלום עולם גדול מאוד",
+			expectedWrappedString: "* שלום עולם\nגדול מאוד",
+		},
+		{
+			testName:              "logical order right-to-left paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* ").UsingBidiMode(text.BidiLogicalOrder),
+			unwrappedString:       "שלום עולם גדול מאוד",
+			expectedWrappedString: " שלום עולם *\n   גדול מאוד",
+		},
+		{
+			testName:              "visual order right-to-left paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* ").UsingBidiMode(text.BidiVisualOrder),
+			unwrappedString:       "שלום עולם גדול מאוד",
+			expectedWrappedString: " םלוע םולש *\n   דואמ לודג",
+		},
+		{
+			testName:              "logical order left-to-right paragraph is unchanged",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* ").UsingBidiMode(text.BidiLogicalOrder),
+			unwrappedString:       "abc אבג 123 דהו def",
+			expectedWrappedString: "* abc אבג\n123 דהו def",
+		},
+		{
+			testName:              "visual order left-to-right paragraph with number after right-to-left text on the previous row",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* ").UsingBidiMode(text.BidiVisualOrder),
+			unwrappedString:       "abc אבג 123 דהו def",
+			expectedWrappedString: "* abc גבא\nוהד 123 def",
+		},
+		{
+			testName:              "visual order right-to-left paragraph with embedded left-to-right text and mirrored brackets",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* ").UsingBidiMode(text.BidiVisualOrder),
+			unwrappedString:       "אבג abc def (דהו) 123",
+			expectedWrappedString: "   abc גבא *\n   (והד) def\n         123",
+		},
+		{
+			testName:              "visual order keeps combining marks after their character",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingBidiMode(text.BidiVisualOrder),
+			unwrappedString:       "שָלּום",
+			expectedWrappedString: "      םולּשָ",
+		},
+		{
+			testName:              "centered right-to-left paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingBidiMode(text.BidiVisualOrder).UsingRightToLeftAlignment(text.AlignCenter),
+			unwrappedString:       "שלום",
+			expectedWrappedString: "    םולש    ",
+		},
+		{
+			testName:              "unpadded right-to-left paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingBidiMode(text.BidiVisualOrder).UsingRightToLeftAlignment(text.AlignLeft),
+			unwrappedString:       "שלום",
+			expectedWrappedString: "םולש",
+		},
+		{
+			testName: "forced right-to-left direction",
+			wrapper: text.NewWrapper().UsingRowWidth(12).UsingIndentStringForRowsAfterTheFirst("> ").
+				UsingBidiMode(text.BidiLogicalOrder).UsingParagraphDirection(text.RightToLeftParagraph),
+			unwrappedString:       "hello world again",
+			expectedWrappedString: " hello world\n     again >",
+		},
+		{
+			testName: "forced left-to-right direction",
+			wrapper: text.NewWrapper().UsingRowWidth(12).
+				UsingBidiMode(text.BidiVisualOrder).UsingParagraphDirection(text.LeftToRightParagraph),
+			unwrappedString:       "שלום עולם",
+			expectedWrappedString: "םלוע םולש",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ColumnBalancing determines how a ColumnLayout distributes wrapped rows across its columns.
+type ColumnBalancing int
+
+const (
+	// FillColumnsFirst fills each column to the column height before moving to the next column. If no
+	// column height is set, the column height is the number of wrapped rows divided by the number of
+	// columns, rounded up, so the last column may be shorter than the others (or even empty).
+	FillColumnsFirst ColumnBalancing = iota
+
+	// BalanceColumnHeights distributes the wrapped rows so that no two columns differ in height by more
+	// than one row. Columns on the left receive the extra rows.
+	BalanceColumnHeights
+)
+
+// ColumnLayout lays out text in side-by-side columns, in the manner of newspaper columns. The text is
+// wrapped by a Wrapper into rows that are as wide as a single column. Those rows are then distributed
+// across the columns, from top to bottom and then from left to right. The columns are joined by a
+// (configurable) gutter string. Each column except the last is padded with spaces to the column width.
+// The column width is the total row width, less the width of the gutters, divided by the number of
+// columns (rounded down). Width is measured using the Measurer of the Wrapper used for the columns, so
+// multibyte and wide characters are padded correctly.
+//
+// If a column height is set and the text produces more rows than fit in all columns at that height,
+// the overflow is laid out in a new block of columns, separated from the previous block by an empty row.
+type ColumnLayout struct {
+	numberOfColumns  uint
+	totalRowWidth    uint
+	gutterString     string
+	columnBalancing  ColumnBalancing
+	rowsPerColumn    uint
+	wrapperForColumn *Wrapper
+}
+
+// NewColumnLayout creates a ColumnLayout with the provided number of columns. By default, the total
+// row width is 79, the gutter is two spaces, rows fill each column first, there is no fixed column
+// height, and the text is wrapped by a Wrapper created with NewWrapper().
+func NewColumnLayout(numberOfColumns uint) *ColumnLayout {
+	if numberOfColumns == 0 {
+		panic("ColumnLayout must have at least one column")
+	}
+
+	return &ColumnLayout{
+		numberOfColumns:  numberOfColumns,
+		totalRowWidth:    79,
+		gutterString:     "  ",
+		columnBalancing:  FillColumnsFirst,
+		rowsPerColumn:    0,
+		wrapperForColumn: NewWrapper(),
+	}
+}
+
+// ChangeTotalRowWidthTo changes the width of a row across all columns, including the gutters.
+func (layout *ColumnLayout) ChangeTotalRowWidthTo(numberOfColumns uint) *ColumnLayout {
+	layout.totalRowWidth = numberOfColumns
+	return layout
+}
+
+// UsingTotalRowWidth is the same as ChangeTotalRowWidthTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (layout *ColumnLayout) UsingTotalRowWidth(numberOfColumns uint) *ColumnLayout {
+	return layout.ChangeTotalRowWidthTo(numberOfColumns)
+}
+
+// ChangeGutterTo changes the string that is placed between adjacent columns.
+func (layout *ColumnLayout) ChangeGutterTo(gutter string) *ColumnLayout {
+	layout.gutterString = gutter
+	return layout
+}
+
+// UsingGutter is the same as ChangeGutterTo(), but provides a more readable name if this is chained with the
+// constructor.
+func (layout *ColumnLayout) UsingGutter(gutter string) *ColumnLayout {
+	return layout.ChangeGutterTo(gutter)
+}
+
+// ChangeColumnBalancingTo changes the method used to distribute rows across the columns.
+func (layout *ColumnLayout) ChangeColumnBalancingTo(balancing ColumnBalancing) *ColumnLayout {
+	layout.columnBalancing = balancing
+	return layout
+}
+
+// UsingColumnBalancing is the same as ChangeColumnBalancingTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (layout *ColumnLayout) UsingColumnBalancing(balancing ColumnBalancing) *ColumnLayout {
+	return layout.ChangeColumnBalancingTo(balancing)
+}
+
+// ChangeColumnHeightTo sets the maximum number of rows in each column. A value of 0 (the default) means
+// there is no maximum, so all of the text is laid out in a single block of columns.
+func (layout *ColumnLayout) ChangeColumnHeightTo(numberOfRows uint) *ColumnLayout {
+	layout.rowsPerColumn = numberOfRows
+	return layout
+}
+
+// UsingColumnHeight is the same as ChangeColumnHeightTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (layout *ColumnLayout) UsingColumnHeight(numberOfRows uint) *ColumnLayout {
+	return layout.ChangeColumnHeightTo(numberOfRows)
+}
+
+// ChangeWrapperTo sets the Wrapper used to wrap text into column rows. This can be used to set row
+// indents for each column. The row width of the provided Wrapper is ignored, and the Wrapper itself is
+// not changed.
+func (layout *ColumnLayout) ChangeWrapperTo(wrapper *Wrapper) *ColumnLayout {
+	layout.wrapperForColumn = wrapper
+	return layout
+}
+
+// UsingWrapper is the same as ChangeWrapperTo(), but provides a more readable name if this is chained
+// with the constructor.
+func (layout *ColumnLayout) UsingWrapper(wrapper *Wrapper) *ColumnLayout {
+	return layout.ChangeWrapperTo(wrapper)
+}
+
+// ColumnWidth returns the width of each column, based on the total row width, the number of columns and
+// the width of the gutter. It returns an error if the columns would have no room for text.
+func (layout *ColumnLayout) ColumnWidth() (uint, error) {
+	widthOfAllGutters := int(layout.numberOfColumns-1) * layout.wrapperForColumn.StringWidth(layout.gutterString)
+	columnWidth := (int(layout.totalRowWidth) - widthOfAllGutters) / int(layout.numberOfColumns)
+
+	if columnWidth <= widthOfRunesUsing(layout.wrapperForColumn.columnAdvancer(), layout.wrapperForColumn.initialLineIndentString) || columnWidth <= widthOfRunesUsing(layout.wrapperForColumn.columnAdvancer(), layout.wrapperForColumn.subsequentLinesIndentString) {
+		return 0, fmt.Errorf("total row width (%d) is too small for %d columns", layout.totalRowWidth, layout.numberOfColumns)
+	}
+
+	return uint(columnWidth), nil
+}
+
+// LayOutStringText wraps the provided string into column rows, then distributes those rows across the
+// columns. It returns the combined rows, separated by the line break sequence of the Wrapper, or an error
+// if one occurs.
+func (layout *ColumnLayout) LayOutStringText(unwrappedString string) (laidOutText string, err error) {
+	return layout.layOutUsing(func(wrapper *Wrapper) (string, error) {
+		return wrapper.WrapStringText(unwrappedString)
+	})
+}
+
+// LayOutUTF8TextFromAReader is the same as LayOutStringText(), but reads UTF-8 text from the provided
+// reader until io.EOF.
+func (layout *ColumnLayout) LayOutUTF8TextFromAReader(reader io.Reader) (laidOutText string, err error) {
+	return layout.layOutUsing(func(wrapper *Wrapper) (string, error) {
+		return wrapper.WrapUTF8TextFromAReader(reader)
+	})
+}
+
+func (layout *ColumnLayout) layOutUsing(wrapUsing func(wrapper *Wrapper) (string, error)) (string, error) {
+	columnWidth, err := layout.ColumnWidth()
+	if err != nil {
+		return "", err
+	}
+
+	wrapperForColumn := *layout.wrapperForColumn
+	wrapperForColumn.fontMetrics = nil
+	wrapperForColumn.columnsPerRow = columnWidth
+
+	wrappedText, err := wrapUsing(&wrapperForColumn)
+	if err != nil {
+		return "", err
+	}
+
+	if wrappedText == "" {
+		return "", nil
+	}
+
+	wrappedRows := strings.Split(wrappedText, wrapperForColumn.lineBreakSequence)
+
+	laidOutBlocks := make([]string, 0, 1)
+	for len(wrappedRows) > 0 {
+		rowsInThisBlock := wrappedRows
+		if layout.rowsPerColumn > 0 && uint(len(wrappedRows)) > layout.rowsPerColumn*layout.numberOfColumns {
+			rowsInThisBlock = wrappedRows[:layout.rowsPerColumn*layout.numberOfColumns]
+		}
+
+		laidOutBlocks = append(laidOutBlocks, layout.combineRowsIntoColumns(rowsInThisBlock, int(columnWidth), wrapperForColumn.lineBreakSequence))
+		wrappedRows = wrappedRows[len(rowsInThisBlock):]
+	}
+
+	return strings.Join(laidOutBlocks, wrapperForColumn.lineBreakSequence+wrapperForColumn.lineBreakSequence), nil
+}
+
+func (layout *ColumnLayout) combineRowsIntoColumns(wrappedRows []string, columnWidth int, lineBreakSequence string) string {
+	columns := layout.distributeRowsAcrossColumns(wrappedRows)
+
+	combinedRows := make([]string, len(columns[0]))
+	for rowIndex := range combinedRows {
+		var combinedRow strings.Builder
+
+		for columnIndex, column := range columns {
+			if rowIndex >= len(column) {
+				break
+			}
+
+			if columnIndex > 0 {
+				combinedRow.WriteString(layout.gutterString)
+			}
+
+			if columnIndex < len(columns)-1 && rowIndex < len(columns[columnIndex+1]) {
+				combinedRow.WriteString(layout.wrapperForColumn.Pad(column[rowIndex], uint(columnWidth), AlignLeft))
+			} else {
+				combinedRow.WriteString(column[rowIndex])
+			}
+		}
+
+		combinedRows[rowIndex] = combinedRow.String()
+	}
+
+	return strings.Join(combinedRows, lineBreakSequence)
+}
+
+func (layout *ColumnLayout) distributeRowsAcrossColumns(wrappedRows []string) [][]string {
+	numberOfColumns := int(layout.numberOfColumns)
+	columns := make([][]string, 0, numberOfColumns)
+
+	switch layout.columnBalancing {
+	case BalanceColumnHeights:
+		minimumRowsPerColumn := len(wrappedRows) / numberOfColumns
+		columnsWithAnExtraRow := len(wrappedRows) % numberOfColumns
+
+		for columnIndex := 0; columnIndex < numberOfColumns && len(wrappedRows) > 0; columnIndex++ {
+			rowsInThisColumn := minimumRowsPerColumn
+			if columnIndex < columnsWithAnExtraRow {
+				rowsInThisColumn++
+			}
+
+			columns = append(columns, wrappedRows[:rowsInThisColumn])
+			wrappedRows = wrappedRows[rowsInThisColumn:]
+		}
+
+	default:
+		rowsPerColumn := int(layout.rowsPerColumn)
+		if rowsPerColumn == 0 {
+			rowsPerColumn = (len(wrappedRows) + numberOfColumns - 1) / numberOfColumns
+		}
+
+		for len(wrappedRows) > 0 {
+			rowsInThisColumn := rowsPerColumn
+			if rowsInThisColumn > len(wrappedRows) {
+				rowsInThisColumn = len(wrappedRows)
+			}
+
+			columns = append(columns, wrappedRows[:rowsInThisColumn])
+			wrappedRows = wrappedRows[rowsInThisColumn:]
+		}
+	}
+
+	return columns
+}
@@ -0,0 +1,106 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type ColumnLayoutTestCase struct {
+	testName            string
+	layout              *text.ColumnLayout
+	unwrappedString     string
+	expectedLaidOutText string
+	expectAnError       bool
+}
+
+func (testCase *ColumnLayoutTestCase) RunTest() error {
+	laidOutText, err := testCase.layout.LayOutStringText(testCase.unwrappedString)
+
+	if testCase.expectAnError {
+		if err == nil {
+			return fmt.Errorf("[%s] expected error, got none", testCase.testName)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("[%s] expected no error, got = (%s)", testCase.testName, err)
+	}
+
+	if laidOutText != testCase.expectedLaidOutText {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedLaidOutText, laidOutText)
+	}
+
+	return nil
+}
+
+var unwrappedColumnString01 string = "The quick brown fox jumps over the lazy dog. ḂϞ∀∁∂∃ multibyte ∄∅∆∇ runes are padded correctly when they appear in the columns."
+
+func TestColumnLayout(t *testing.T) {
+	testCases := []*ColumnLayoutTestCase{
+		{
+			testName:        "fill columns first",
+			layout:          text.NewColumnLayout(3).UsingTotalRowWidth(40).UsingGutter(" | "),
+			unwrappedString: unwrappedColumnString01,
+			expectedLaidOutText: "" +
+				"The quick   | multibyte   | appear in\n" +
+				"brown fox   | ∄∅∆∇ runes  | the\n" +
+				"jumps over  | are padded  | columns.\n" +
+				"the lazy    | correctly\n" +
+				"dog. ḂϞ∀∁∂∃ | when they",
+		},
+		{
+			testName:        "balance column heights",
+			layout:          text.NewColumnLayout(3).UsingTotalRowWidth(40).UsingGutter(" | ").UsingColumnBalancing(text.BalanceColumnHeights),
+			unwrappedString: unwrappedColumnString01,
+			expectedLaidOutText: "" +
+				"The quick   | multibyte   | when they\n" +
+				"brown fox   | ∄∅∆∇ runes  | appear in\n" +
+				"jumps over  | are padded  | the\n" +
+				"the lazy    | correctly   | columns.\n" +
+				"dog. ḂϞ∀∁∂∃",
+		},
+		{
+			testName:        "fixed column height",
+			layout:          text.NewColumnLayout(2).UsingTotalRowWidth(30).UsingColumnHeight(2),
+			unwrappedString: unwrappedColumnString01,
+			expectedLaidOutText: "" +
+				"The quick       jumps over the\n" +
+				"brown fox       lazy dog.\n" +
+				"\n" +
+				"ḂϞ∀∁∂∃          runes are\n" +
+				"multibyte ∄∅∆∇  padded\n" +
+				"\n" +
+				"correctly when  the columns.\n" +
+				"they appear in",
+		},
+		{
+			testName:        "wrapper with indent",
+			layout:          text.NewColumnLayout(2).UsingTotalRowWidth(30).UsingWrapper(text.NewWrapper().UsingIndentStringForRowsAfterTheFirst("  ")),
+			unwrappedString: "one two three four five six seven",
+			expectedLaidOutText: "" +
+				"one two three     six seven\n" +
+				"  four five",
+		},
+		{
+			testName:            "empty input",
+			layout:              text.NewColumnLayout(2),
+			unwrappedString:     "",
+			expectedLaidOutText: "",
+		},
+		{
+			testName:        "row width too small",
+			layout:          text.NewColumnLayout(4).UsingTotalRowWidth(10).UsingGutter(" | "),
+			unwrappedString: unwrappedColumnString01,
+			expectAnError:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
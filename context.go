@@ -0,0 +1,64 @@
+package text
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"golang.org/x/text/encoding"
+)
+
+// WrapUTF8TextFromAReaderWithContext is the same as WrapUTF8TextFromAReader(), but stops reading when ctx is
+// cancelled or its deadline passes. The context is checked before each Read() from reader, so a Read() that is
+// already blocked is not interrupted; to stop one, reader must also be closed (or have its own deadline). If
+// wrapping is stopped by ctx, the returned error is ctx.Err(), and the wrapped text has the rows produced up to that
+// point.
+func (wrapper *Wrapper) WrapUTF8TextFromAReaderWithContext(ctx context.Context, reader io.Reader) (wrappedText string, err error) {
+	wrappedText, _, err = wrapper.WrapUTF8TextFromAReaderWithContextAndReportTruncation(ctx, reader)
+	return wrappedText, err
+}
+
+// WrapUTF8TextFromAReaderWithContextAndReportTruncation is the same as WrapUTF8TextFromAReaderWithContext(), but
+// also reports whether rows were dropped because the wrapped text had more rows than the maximum set by
+// ChangeMaximumRowsTo().
+func (wrapper *Wrapper) WrapUTF8TextFromAReaderWithContextAndReportTruncation(ctx context.Context, reader io.Reader) (wrappedText string, contentWasDropped bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return "", false, err
+	}
+
+	wrappedText, contentWasDropped, err = wrapper.WrapUTF8TextFromAReaderAndReportTruncation(&contextReader{ctx: ctx, source: reader})
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return wrappedText, contentWasDropped, ctx.Err()
+	}
+
+	return wrappedText, contentWasDropped, err
+}
+
+// WrapTextFromAReaderWithEncodingAndContext is the same as WrapTextFromAReaderWithEncoding(), but stops reading
+// when ctx is cancelled or its deadline passes, as described for WrapUTF8TextFromAReaderWithContext().
+func (wrapper *Wrapper) WrapTextFromAReaderWithEncodingAndContext(ctx context.Context, reader io.Reader, inputEncoding encoding.Encoding) (wrappedText string, err error) {
+	decodedReader, _, err := wrapper.decodingReaderFor(&contextReader{ctx: ctx, source: reader}, inputEncoding)
+	if err != nil {
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return "", ctx.Err()
+		}
+
+		return "", err
+	}
+
+	return wrapper.WrapUTF8TextFromAReaderWithContext(ctx, decodedReader)
+}
+
+// contextReader is an io.Reader that returns ctx.Err() instead of reading from source once ctx is done.
+type contextReader struct {
+	ctx    context.Context
+	source io.Reader
+}
+
+func (reader *contextReader) Read(p []byte) (int, error) {
+	if err := reader.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return reader.source.Read(p)
+}
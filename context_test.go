@@ -0,0 +1,116 @@
+package text_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/text"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// readerThatCancels returns each of its chunks from a separate Read(), and calls cancel after returning the chunk at
+// indexOfChunkThatCancels.
+type readerThatCancels struct {
+	chunks                  []string
+	indexOfChunkThatCancels int
+	cancel                  context.CancelFunc
+	indexOfNextChunk        int
+}
+
+func (reader *readerThatCancels) Read(p []byte) (int, error) {
+	if reader.indexOfNextChunk == len(reader.chunks) {
+		return 0, io.EOF
+	}
+
+	bytesCopied := copy(p, reader.chunks[reader.indexOfNextChunk])
+	if reader.indexOfNextChunk == reader.indexOfChunkThatCancels {
+		reader.cancel()
+	}
+
+	reader.indexOfNextChunk++
+	return bytesCopied, nil
+}
+
+// endlessSlowReader returns "word " from every Read(), after a delay.
+type endlessSlowReader struct {
+	delay time.Duration
+}
+
+func (reader *endlessSlowReader) Read(p []byte) (int, error) {
+	time.Sleep(reader.delay)
+	return copy(p, "word "), nil
+}
+
+func TestWrapWithContextCancelledBetweenReads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := &readerThatCancels{
+		chunks:                  []string{"one two ", "three ", "four five"},
+		indexOfChunkThatCancels: 1,
+		cancel:                  cancel,
+	}
+
+	wrappedString, err := text.NewWrapper().UsingRowWidth(8).WrapUTF8TextFromAReaderWithContext(ctx, reader)
+	if err != context.Canceled {
+		t.Fatalf("expected error = (%v), got = (%v)", context.Canceled, err)
+	}
+
+	if wrappedString != "one two\nthree" {
+		t.Errorf("expected = (%q), got = (%q)", "one two\nthree", wrappedString)
+	}
+}
+
+func TestWrapWithContextThatIsAlreadyDone(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	wrappedString, err := text.NewWrapper().WrapUTF8TextFromAReaderWithContext(ctx, strings.NewReader("one two"))
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected error = (%v), got = (%v)", context.DeadlineExceeded, err)
+	}
+
+	if wrappedString != "" {
+		t.Errorf("expected = (%q), got = (%q)", "", wrappedString)
+	}
+}
+
+func TestWrapWithContextDeadlineOnAnEndlessReader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	wrappedString, err := text.NewWrapper().UsingRowWidth(9).WrapUTF8TextFromAReaderWithContext(ctx, &endlessSlowReader{delay: 5 * time.Millisecond})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected error = (%v), got = (%v)", context.DeadlineExceeded, err)
+	}
+
+	for _, row := range strings.Split(wrappedString, "\n") {
+		if row != "word word" && row != "word" {
+			t.Errorf("unexpected row (%q) in (%q)", row, wrappedString)
+			break
+		}
+	}
+}
+
+func TestWrapWithEncodingAndContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := &readerThatCancels{
+		chunks:                  []string{"caf\xe9 cr\xe8me ", "br\xfbl\xe9e"},
+		indexOfChunkThatCancels: 0,
+		cancel:                  cancel,
+	}
+
+	wrappedString, err := text.NewWrapper().UsingRowWidth(10).WrapTextFromAReaderWithEncodingAndContext(ctx, reader, charmap.ISO8859_1)
+	if err != context.Canceled {
+		t.Fatalf("expected error = (%v), got = (%v)", context.Canceled, err)
+	}
+
+	if wrappedString != "café crème" {
+		t.Errorf("expected = (%q), got = (%q)", "café crème", wrappedString)
+	}
+}
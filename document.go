@@ -0,0 +1,245 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrEditOutOfRange is returned by WrappedDocument.Edit() if the edited text is not in the document.
+var ErrEditOutOfRange = errors.New("edit is outside of the document")
+
+// WrappedDocument is text that is wrapped as by WrapParagraphsFromAReader(), and that can be edited without
+// wrapping all of it again. It is meant for editors, which change a few characters at a time, but must show the
+// wrapped rows of the whole text after each change.
+//
+// The rows of a WrappedDocument are the rows that WrapParagraphsFromAReader() would write, without the final line
+// break: the wrapped rows of each paragraph, with an empty row between paragraphs. When the text is edited, only
+// the paragraphs that the edit can change are wrapped again, and Edit() reports the rows that they replaced.
+type WrappedDocument struct {
+	wrapper    Wrapper
+	text       string
+	paragraphs []documentParagraph
+	rows       []string
+}
+
+// documentParagraph is a paragraph of a WrappedDocument. Its text is text[startOffset:endOffset] of the document,
+// which is its lines, including their line breaks, and its wrapped rows are rows[firstRow:firstRow+numberOfRows] of
+// the document.
+type documentParagraph struct {
+	startOffset  int
+	endOffset    int
+	firstRow     int
+	numberOfRows int
+}
+
+// ChangedRows is the range of the rows of a WrappedDocument that an edit replaced. The rows before FirstRow, and the
+// rows after the replaced rows, are the same before and after the edit.
+type ChangedRows struct {
+	// FirstRow is the index of the first row that was replaced.
+	FirstRow int
+
+	// NumberOfRowsRemoved is the number of rows, starting at FirstRow, that were replaced.
+	NumberOfRowsRemoved int
+
+	// NumberOfRowsInserted is the number of rows, starting at FirstRow, that replaced them.
+	NumberOfRowsInserted int
+}
+
+// WrapDocument wraps text, as by WrapParagraphsFromAReader(), into a WrappedDocument that can then be edited. The
+// WrappedDocument uses a copy of the Wrapper, so changes to the Wrapper after this returns do not change how the
+// document is wrapped. If wrapping a paragraph fails, the error is returned (as from WrapParagraphsFromAReader()),
+// and no WrappedDocument is returned.
+func (wrapper *Wrapper) WrapDocument(text string) (*WrappedDocument, error) {
+	document := &WrappedDocument{
+		wrapper:    *wrapper,
+		text:       "",
+		paragraphs: nil,
+		rows:       nil,
+	}
+
+	if _, err := document.Edit(0, 0, text); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// Text returns the text of the document.
+func (document *WrappedDocument) Text() string {
+	return document.text
+}
+
+// Rows returns the wrapped rows of the document. The returned slice is used by the document, so it must not be
+// changed, and it is only valid until the next call to Edit().
+func (document *WrappedDocument) Rows() []string {
+	return document.rows
+}
+
+// Edit replaces deletedLength bytes of the text of the document, starting at byte offset, with insertedText, and
+// wraps the paragraphs that the edit can change again. Those are the paragraphs that have a line that is changed by
+// the edit, or that are next to such a line, because a blank line that is changed can join two paragraphs and a
+// line that becomes blank can split one. The other paragraphs are not wrapped again, so an edit in one paragraph
+// of a long document takes about as long as wrapping that paragraph. Edit returns the range of rows that were
+// replaced. Rows that were wrapped again, but did not change, are not in the range.
+//
+// If offset and deletedLength are not a range of bytes in the text, an error that wraps ErrEditOutOfRange is
+// returned. If wrapping a paragraph fails, the error is returned, with the position in a WrapError adjusted to be
+// in the edited text, as it would be from WrapParagraphsFromAReader(). In both cases the document is not changed.
+func (document *WrappedDocument) Edit(offset int, deletedLength int, insertedText string) (ChangedRows, error) {
+	if offset < 0 || deletedLength < 0 || offset+deletedLength > len(document.text) {
+		return ChangedRows{}, fmt.Errorf("%w: cannot replace %d bytes at offset %d in %d bytes", ErrEditOutOfRange, deletedLength, offset, len(document.text))
+	}
+
+	endOfDeletedText := offset + deletedLength
+	startOfFirstEditedLine := strings.LastIndexByte(document.text[:offset], '\n') + 1
+	endOfLastEditedLine := len(document.text)
+	if indexOfLineFeed := strings.IndexByte(document.text[endOfDeletedText:], '\n'); indexOfLineFeed >= 0 {
+		endOfLastEditedLine = endOfDeletedText + indexOfLineFeed + 1
+	}
+
+	// A paragraph that ends before the line that starts the edit is followed by a blank line that the edit does not
+	// change, so it stays the same paragraph. The same is true of a paragraph that starts after the line that ends
+	// the edit. Every paragraph between them is wrapped again.
+	indexOfFirstEditedParagraph := sort.Search(len(document.paragraphs), func(i int) bool {
+		return document.paragraphs[i].endOffset >= startOfFirstEditedLine
+	})
+	indexOfFirstParagraphAfterTheEdit := sort.Search(len(document.paragraphs), func(i int) bool {
+		return document.paragraphs[i].startOffset > endOfLastEditedLine
+	})
+
+	startOfRewrappedText := 0
+	firstChangedRow := 0
+	if indexOfFirstEditedParagraph > 0 {
+		paragraphBeforeTheEdit := document.paragraphs[indexOfFirstEditedParagraph-1]
+		startOfRewrappedText = paragraphBeforeTheEdit.endOffset
+		firstChangedRow = paragraphBeforeTheEdit.firstRow + paragraphBeforeTheEdit.numberOfRows
+	}
+
+	endOfRewrappedText := len(document.text)
+	endOfChangedRows := len(document.rows)
+	if indexOfFirstParagraphAfterTheEdit < len(document.paragraphs) {
+		paragraphAfterTheEdit := document.paragraphs[indexOfFirstParagraphAfterTheEdit]
+		endOfRewrappedText = paragraphAfterTheEdit.startOffset
+		endOfChangedRows = paragraphAfterTheEdit.firstRow
+	}
+
+	editedText := document.text[:offset] + insertedText + document.text[endOfDeletedText:]
+	changeInLength := len(insertedText) - deletedLength
+
+	rewrappedParagraphs, rewrappedRows, err := document.wrapParagraphsIn(editedText, startOfRewrappedText, endOfRewrappedText+changeInLength, firstChangedRow,
+		indexOfFirstEditedParagraph > 0, indexOfFirstParagraphAfterTheEdit < len(document.paragraphs))
+	if err != nil {
+		return ChangedRows{}, err
+	}
+
+	changeInNumberOfRows := len(rewrappedRows) - (endOfChangedRows - firstChangedRow)
+	changedRows := changedRowsBetween(document.rows[firstChangedRow:endOfChangedRows], rewrappedRows, firstChangedRow)
+
+	document.text = editedText
+	document.rows = append(document.rows[:firstChangedRow], append(rewrappedRows, document.rows[endOfChangedRows:]...)...)
+	document.paragraphs = append(document.paragraphs[:indexOfFirstEditedParagraph], append(rewrappedParagraphs, document.paragraphs[indexOfFirstParagraphAfterTheEdit:]...)...)
+
+	for i := indexOfFirstEditedParagraph + len(rewrappedParagraphs); i < len(document.paragraphs); i++ {
+		document.paragraphs[i].startOffset += changeInLength
+		document.paragraphs[i].endOffset += changeInLength
+		document.paragraphs[i].firstRow += changeInNumberOfRows
+	}
+
+	return changedRows, nil
+}
+
+// wrapParagraphsIn wraps the paragraphs in text[startOffset:endOffset], which starts and ends at the start of a line.
+// It returns the paragraphs and the rows that replace the rows from firstRow, including the empty rows between
+// the paragraphs, and the empty rows that separate them from a paragraph before them (if there is a paragraph
+// before startOffset) and from a paragraph after them (if there is a paragraph after endOffset).
+func (document *WrappedDocument) wrapParagraphsIn(text string, startOffset int, endOffset int, firstRow int, thereIsAParagraphBefore bool, thereIsAParagraphAfter bool) ([]documentParagraph, []string, error) {
+	var wrappedParagraphs []documentParagraph
+	var wrappedRows []string
+	var bufferOfWrappedText []byte
+
+	paragraphs := newParagraphReader(strings.NewReader(text[startOffset:endOffset]), 0)
+	for {
+		nextParagraph, err := paragraphs.readParagraph()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if thereIsAParagraphBefore || len(wrappedParagraphs) > 0 {
+			wrappedRows = append(wrappedRows, "")
+		}
+
+		bufferOfWrappedText, err = document.wrapper.AppendWrapped(bufferOfWrappedText[:0], nextParagraph.text)
+		if err != nil {
+			return nil, nil, errorAtPositionInTheDocument(err, text, startOffset, nextParagraph, firstRow+len(wrappedRows))
+		}
+
+		rowsOfParagraph := strings.Split(string(bufferOfWrappedText), document.wrapper.lineBreakSequence)
+		startOfParagraph := startOffset + int(nextParagraph.position.byteOffset)
+
+		wrappedParagraphs = append(wrappedParagraphs, documentParagraph{
+			startOffset:  startOfParagraph,
+			endOffset:    startOfParagraph + len(nextParagraph.text),
+			firstRow:     firstRow + len(wrappedRows),
+			numberOfRows: len(rowsOfParagraph),
+		})
+		wrappedRows = append(wrappedRows, rowsOfParagraph...)
+	}
+
+	if thereIsAParagraphAfter && (thereIsAParagraphBefore || len(wrappedParagraphs) > 0) {
+		wrappedRows = append(wrappedRows, "")
+	}
+
+	return wrappedParagraphs, wrappedRows, nil
+}
+
+// changedRowsBetween returns the range of rows that rowsAfterTheEdit replace, when they replace rowsBeforeTheEdit,
+// which start at firstRow. Rows that are the same at the start and at the end of both are not in the range, so an
+// edit that does not move a row break changes only the row that it is in.
+func changedRowsBetween(rowsBeforeTheEdit []string, rowsAfterTheEdit []string, firstRow int) ChangedRows {
+	numberOfRowsThatAreTheSameAtTheStart := 0
+	for numberOfRowsThatAreTheSameAtTheStart < len(rowsBeforeTheEdit) && numberOfRowsThatAreTheSameAtTheStart < len(rowsAfterTheEdit) &&
+		rowsBeforeTheEdit[numberOfRowsThatAreTheSameAtTheStart] == rowsAfterTheEdit[numberOfRowsThatAreTheSameAtTheStart] {
+		numberOfRowsThatAreTheSameAtTheStart++
+	}
+
+	rowsBeforeTheEdit = rowsBeforeTheEdit[numberOfRowsThatAreTheSameAtTheStart:]
+	rowsAfterTheEdit = rowsAfterTheEdit[numberOfRowsThatAreTheSameAtTheStart:]
+
+	numberOfRowsThatAreTheSameAtTheEnd := 0
+	for numberOfRowsThatAreTheSameAtTheEnd < len(rowsBeforeTheEdit) && numberOfRowsThatAreTheSameAtTheEnd < len(rowsAfterTheEdit) &&
+		rowsBeforeTheEdit[len(rowsBeforeTheEdit)-1-numberOfRowsThatAreTheSameAtTheEnd] == rowsAfterTheEdit[len(rowsAfterTheEdit)-1-numberOfRowsThatAreTheSameAtTheEnd] {
+		numberOfRowsThatAreTheSameAtTheEnd++
+	}
+
+	return ChangedRows{
+		FirstRow:             firstRow + numberOfRowsThatAreTheSameAtTheStart,
+		NumberOfRowsRemoved:  len(rowsBeforeTheEdit) - numberOfRowsThatAreTheSameAtTheEnd,
+		NumberOfRowsInserted: len(rowsAfterTheEdit) - numberOfRowsThatAreTheSameAtTheEnd,
+	}
+}
+
+// errorAtPositionInTheDocument adjusts the position in a WrapError from wrapping sourceParagraph, which was read
+// from text[startOffset:], so that it is from the start of text, and so that the row is counted from the first row
+// of the document, given that the paragraph is wrapped into the rows that start at rowOfParagraph.
+func errorAtPositionInTheDocument(err error, text string, startOffset int, sourceParagraph *paragraph, rowOfParagraph int) error {
+	var wrapError *WrapError
+	if !errors.As(err, &wrapError) {
+		return err
+	}
+
+	textBeforeTheParagraphs := text[:startOffset]
+
+	return &WrapError{
+		Err:        wrapError.Err,
+		ByteOffset: int64(startOffset) + sourceParagraph.position.byteOffset + wrapError.ByteOffset,
+		RuneOffset: int64(utf8.RuneCountInString(textBeforeTheParagraphs)) + sourceParagraph.position.runeOffset + wrapError.RuneOffset,
+		Line:       strings.Count(textBeforeTheParagraphs, "\n") + sourceParagraph.position.line + wrapError.Line - 1,
+		Row:        rowOfParagraph + wrapError.Row,
+	}
+}
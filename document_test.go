@@ -0,0 +1,361 @@
+package text_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type documentEdit struct {
+	offset        int
+	deletedLength int
+	insertedText  string
+}
+
+type WrappedDocumentTestCase struct {
+	testName            string
+	wrapper             *text.Wrapper
+	initialText         string
+	edits               []documentEdit
+	expectedChangedRows []text.ChangedRows
+	expectedRows        []string
+}
+
+func (testCase *WrappedDocumentTestCase) RunTest() error {
+	document, err := testCase.wrapper.WrapDocument(testCase.initialText)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if err := errorUnlessTheRowsOfTheDocumentAreTheWrappedParagraphs(testCase.wrapper, document); err != nil {
+		return fmt.Errorf("[%s] %s", testCase.testName, err)
+	}
+
+	for editNumber, edit := range testCase.edits {
+		rowsBeforeTheEdit := append([]string(nil), document.Rows()...)
+
+		changedRows, err := document.Edit(edit.offset, edit.deletedLength, edit.insertedText)
+		if err != nil {
+			return fmt.Errorf("[%s] (edit %d) unexpected error: %s", testCase.testName, editNumber, err)
+		}
+
+		if changedRows != testCase.expectedChangedRows[editNumber] {
+			return fmt.Errorf("[%s] (edit %d) expected changed rows = (%+v), got = (%+v)", testCase.testName, editNumber, testCase.expectedChangedRows[editNumber], changedRows)
+		}
+
+		if err := errorUnlessOnlyTheChangedRowsAreDifferent(rowsBeforeTheEdit, document.Rows(), changedRows); err != nil {
+			return fmt.Errorf("[%s] (edit %d) %s", testCase.testName, editNumber, err)
+		}
+
+		if err := errorUnlessTheRowsOfTheDocumentAreTheWrappedParagraphs(testCase.wrapper, document); err != nil {
+			return fmt.Errorf("[%s] (edit %d) %s", testCase.testName, editNumber, err)
+		}
+	}
+
+	if strings.Join(document.Rows(), "\n") != strings.Join(testCase.expectedRows, "\n") || len(document.Rows()) != len(testCase.expectedRows) {
+		return fmt.Errorf("[%s] expected rows = (%q), got = (%q)", testCase.testName, testCase.expectedRows, document.Rows())
+	}
+
+	return nil
+}
+
+// errorUnlessTheRowsOfTheDocumentAreTheWrappedParagraphs compares the rows of document to the rows written by
+// WrapParagraphsFromAReader() for the text of the document.
+func errorUnlessTheRowsOfTheDocumentAreTheWrappedParagraphs(wrapper *text.Wrapper, document *text.WrappedDocument) error {
+	var wrappedParagraphs bytes.Buffer
+	if err := wrapper.WrapParagraphsFromAReader(strings.NewReader(document.Text()), &wrappedParagraphs); err != nil {
+		return fmt.Errorf("unexpected error from WrapParagraphsFromAReader(): %s", err)
+	}
+
+	expectedRows := []string{}
+	if wrappedParagraphs.Len() > 0 {
+		expectedRows = strings.Split(strings.TrimSuffix(wrappedParagraphs.String(), "\n"), "\n")
+	}
+
+	if len(document.Rows()) != len(expectedRows) || strings.Join(document.Rows(), "\n") != strings.Join(expectedRows, "\n") {
+		return fmt.Errorf("for text (%q), expected rows = (%q), got = (%q)", document.Text(), expectedRows, document.Rows())
+	}
+
+	return nil
+}
+
+func errorUnlessOnlyTheChangedRowsAreDifferent(rowsBeforeTheEdit []string, rowsAfterTheEdit []string, changedRows text.ChangedRows) error {
+	if changedRows.FirstRow < 0 || changedRows.FirstRow+changedRows.NumberOfRowsRemoved > len(rowsBeforeTheEdit) ||
+		len(rowsAfterTheEdit)-changedRows.NumberOfRowsInserted != len(rowsBeforeTheEdit)-changedRows.NumberOfRowsRemoved {
+		return fmt.Errorf("changed rows (%+v) do not fit %d rows before and %d rows after the edit", changedRows, len(rowsBeforeTheEdit), len(rowsAfterTheEdit))
+	}
+
+	for row := 0; row < changedRows.FirstRow; row++ {
+		if rowsAfterTheEdit[row] != rowsBeforeTheEdit[row] {
+			return fmt.Errorf("row %d is before the changed rows (%+v), but changed from (%q) to (%q)", row, changedRows, rowsBeforeTheEdit[row], rowsAfterTheEdit[row])
+		}
+	}
+
+	numberOfRowsAfterTheChangedRows := len(rowsBeforeTheEdit) - changedRows.FirstRow - changedRows.NumberOfRowsRemoved
+	for row := 0; row < numberOfRowsAfterTheChangedRows; row++ {
+		rowBeforeTheEdit := rowsBeforeTheEdit[changedRows.FirstRow+changedRows.NumberOfRowsRemoved+row]
+		rowAfterTheEdit := rowsAfterTheEdit[changedRows.FirstRow+changedRows.NumberOfRowsInserted+row]
+
+		if rowAfterTheEdit != rowBeforeTheEdit {
+			return fmt.Errorf("row (%q) is after the changed rows (%+v), but changed to (%q)", rowBeforeTheEdit, changedRows, rowAfterTheEdit)
+		}
+	}
+
+	return nil
+}
+
+func TestWrappedDocument(t *testing.T) {
+	testCases := []*WrappedDocumentTestCase{
+		{
+			testName:            "empty document",
+			wrapper:             text.NewWrapper().UsingRowWidth(10),
+			initialText:         "",
+			edits:               nil,
+			expectedChangedRows: nil,
+			expectedRows:        []string{},
+		},
+		{
+			testName:    "typing into an empty document",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "",
+			edits: []documentEdit{
+				{offset: 0, deletedLength: 0, insertedText: "one"},
+				{offset: 3, deletedLength: 0, insertedText: " two"},
+				{offset: 7, deletedLength: 0, insertedText: " three"},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 0, NumberOfRowsRemoved: 0, NumberOfRowsInserted: 1},
+				{FirstRow: 0, NumberOfRowsRemoved: 1, NumberOfRowsInserted: 1},
+				{FirstRow: 1, NumberOfRowsRemoved: 0, NumberOfRowsInserted: 1},
+			},
+			expectedRows: []string{"one two", "three"},
+		},
+		{
+			testName:    "editing the middle paragraph",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "one two three\n\nfour five\n\nsix seven eight",
+			edits: []documentEdit{
+				{offset: 20, deletedLength: 4, insertedText: "fifty five"},
+				{offset: 15, deletedLength: 5, insertedText: ""},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 3, NumberOfRowsRemoved: 1, NumberOfRowsInserted: 2},
+				{FirstRow: 3, NumberOfRowsRemoved: 2, NumberOfRowsInserted: 1},
+			},
+			expectedRows: []string{"one two", "three", "", "fifty five", "", "six seven", "eight"},
+		},
+		{
+			testName:    "joining paragraphs by removing the blank line between them",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "one two three\n\nfour five\n\nsix seven eight",
+			edits: []documentEdit{
+				{offset: 14, deletedLength: 1, insertedText: ""},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 1, NumberOfRowsRemoved: 3, NumberOfRowsInserted: 2},
+			},
+			expectedRows: []string{"one two", "three four", "five", "", "six seven", "eight"},
+		},
+		{
+			testName:    "joining paragraphs by typing into the blank line between them",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "one two\n  \nthree",
+			edits: []documentEdit{
+				{offset: 10, deletedLength: 0, insertedText: "and"},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 1, NumberOfRowsRemoved: 2, NumberOfRowsInserted: 1},
+			},
+			expectedRows: []string{"one two", "and three"},
+		},
+		{
+			testName:    "splitting a paragraph by inserting a blank line",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "one two\n\nthree four five six\n\nseven",
+			edits: []documentEdit{
+				{offset: 19, deletedLength: 0, insertedText: "\n\n"},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 3, NumberOfRowsRemoved: 0, NumberOfRowsInserted: 1},
+			},
+			expectedRows: []string{"one two", "", "three four", "", "five six", "", "seven"},
+		},
+		{
+			testName:    "deleting a paragraph",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "one\n\ntwo\n\nthree\n\nfour",
+			edits: []documentEdit{
+				{offset: 5, deletedLength: 5, insertedText: ""},
+				{offset: 0, deletedLength: 5, insertedText: ""},
+				{offset: 5, deletedLength: 6, insertedText: ""},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 2, NumberOfRowsRemoved: 2, NumberOfRowsInserted: 0},
+				{FirstRow: 0, NumberOfRowsRemoved: 2, NumberOfRowsInserted: 0},
+				{FirstRow: 1, NumberOfRowsRemoved: 2, NumberOfRowsInserted: 0},
+			},
+			expectedRows: []string{"three"},
+		},
+		{
+			testName:    "editing whitespace between paragraphs",
+			wrapper:     text.NewWrapper().UsingRowWidth(10),
+			initialText: "one\n\n\n\n\ntwo\n\n\n\n\nthree",
+			edits: []documentEdit{
+				{offset: 6, deletedLength: 0, insertedText: " \t "},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 2, NumberOfRowsRemoved: 0, NumberOfRowsInserted: 0},
+			},
+			expectedRows: []string{"one", "", "two", "", "three"},
+		},
+		{
+			testName:    "indents and CRLF line breaks",
+			wrapper:     text.NewWrapper().UsingRowWidth(12).UsingIndentStringForFirstRow("* ").UsingIndentStringForRowsAfterTheFirst("  "),
+			initialText: "one two three\r\n\r\nfour\r\n",
+			edits: []documentEdit{
+				{offset: 21, deletedLength: 0, insertedText: " five six"},
+			},
+			expectedChangedRows: []text.ChangedRows{
+				{FirstRow: 3, NumberOfRowsRemoved: 1, NumberOfRowsInserted: 2},
+			},
+			expectedRows: []string{"* one two", "  three", "", "* four five", "  six"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestWrappedDocumentWithRandomEdits(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	insertions := []string{"a", " ", "\n", "\n\n", " \n \n", "quick", "jumped ", "über", "日本語", "\r\n", "wrapping incomprehensibilities"}
+
+	for _, wrapper := range []*text.Wrapper{
+		text.NewWrapper().UsingRowWidth(8),
+		text.NewWrapper().UsingRowWidth(20).UsingIndentStringForRowsAfterTheFirst("    "),
+		text.NewWrapper().UsingRowWidth(15).UsingMaximumRows(2),
+	} {
+		document, err := wrapper.WrapDocument("")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		for editNumber := 0; editNumber < 2000; editNumber++ {
+			offset := 0
+			if len(document.Text()) > 0 {
+				offset = random.Intn(len(document.Text()) + 1)
+			}
+
+			deletedLength := 0
+			insertedText := ""
+			if random.Intn(3) == 0 {
+				deletedLength = random.Intn(len(document.Text())-offset+1) / 4
+			} else {
+				insertedText = insertions[random.Intn(len(insertions))]
+			}
+
+			// keep edits on character boundaries, so that the text stays valid UTF-8
+			for offset > 0 && offset < len(document.Text()) && !isStartOfCharacter(document.Text()[offset]) {
+				offset--
+			}
+			for offset+deletedLength < len(document.Text()) && !isStartOfCharacter(document.Text()[offset+deletedLength]) {
+				deletedLength++
+			}
+
+			rowsBeforeTheEdit := append([]string(nil), document.Rows()...)
+
+			changedRows, err := document.Edit(offset, deletedLength, insertedText)
+			if err != nil {
+				t.Fatalf("(edit %d) unexpected error: %s", editNumber, err)
+			}
+
+			if err := errorUnlessOnlyTheChangedRowsAreDifferent(rowsBeforeTheEdit, document.Rows(), changedRows); err != nil {
+				t.Fatalf("(edit %d) %s", editNumber, err)
+			}
+
+			if err := errorUnlessTheRowsOfTheDocumentAreTheWrappedParagraphs(wrapper, document); err != nil {
+				t.Fatalf("(edit %d) %s", editNumber, err)
+			}
+		}
+	}
+}
+
+func isStartOfCharacter(b byte) bool {
+	return b&0xc0 != 0x80
+}
+
+func TestWrappedDocumentEditErrors(t *testing.T) {
+	document, err := text.NewWrapper().UsingRowWidth(10).UsingOutputRowLimit(2).WrapDocument("one two\n\nthree")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, edit := range []documentEdit{
+		{offset: -1, deletedLength: 0, insertedText: "x"},
+		{offset: 3, deletedLength: -1, insertedText: "x"},
+		{offset: 15, deletedLength: 0, insertedText: "x"},
+		{offset: 10, deletedLength: 5, insertedText: "x"},
+	} {
+		if _, err := document.Edit(edit.offset, edit.deletedLength, edit.insertedText); !errors.Is(err, text.ErrEditOutOfRange) {
+			t.Errorf("(offset = %d, deleted length = %d) expected error = (%v), got = (%v)", edit.offset, edit.deletedLength, text.ErrEditOutOfRange, err)
+		}
+	}
+
+	_, err = document.Edit(14, 0, " four five six seven")
+	expectedWrapError := &text.WrapError{
+		Err:        text.ErrLimitExceeded,
+		ByteOffset: 28,
+		RuneOffset: 28,
+		Line:       3,
+		Row:        4,
+	}
+	if err := errorUnlessTheExpectedWrapError(expectedWrapError, err); err != nil {
+		t.Error(err)
+	}
+
+	if document.Text() != "one two\n\nthree" || strings.Join(document.Rows(), "|") != "one two||three" {
+		t.Errorf("expected the document not to change after an error, got text = (%q), rows = (%q)", document.Text(), document.Rows())
+	}
+
+	if _, err := text.NewWrapper().UsingRowWidth(10).UsingWordLengthLimit(3).WrapDocument("one\n\nthree"); !errors.Is(err, text.ErrLimitExceeded) {
+		t.Errorf("expected error = (%v), got = (%v)", text.ErrLimitExceeded, err)
+	}
+}
+
+func BenchmarkWrappedDocumentEdit(b *testing.B) {
+	unwrappedString := strings.Repeat("The quick brown fox jumps over the lazy dög, and then it runs far away into the forêt.\n\n", 2000)
+	wrapper := text.NewWrapper().UsingRowWidth(60)
+
+	b.Run("Edit", func(b *testing.B) {
+		document, err := wrapper.WrapDocument(unwrappedString)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for i := 0; i < b.N; i++ {
+			offset := len(unwrappedString) / 2
+			if _, err := document.Edit(offset, 0, "x"); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := document.Edit(offset, 1, ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("WrapStringText", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := wrapper.WrapStringText(unwrappedString); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
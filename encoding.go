@@ -0,0 +1,140 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// ChangeByteOrderMarkDetectionTo sets whether WrapTextFromAReaderWithEncoding() and WrapEncodedTextFromAReader()
+// look for a byte order mark at the start of the input. If they do, and the input starts with a UTF-8, UTF-16BE or
+// UTF-16LE byte order mark, the input is decoded using that encoding (instead of the encoding provided), and the
+// byte order mark is removed. The default is false.
+func (wrapper *Wrapper) ChangeByteOrderMarkDetectionTo(detectByteOrderMark bool) *Wrapper {
+	wrapper.detectByteOrderMark = detectByteOrderMark
+	return wrapper
+}
+
+// UsingByteOrderMarkDetection is the same as ChangeByteOrderMarkDetectionTo(), but provides a more readable name if
+// this is chained with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingByteOrderMarkDetection(true).UsingRowWidth(40)
+func (wrapper *Wrapper) UsingByteOrderMarkDetection(detectByteOrderMark bool) *Wrapper {
+	return wrapper.ChangeByteOrderMarkDetectionTo(detectByteOrderMark)
+}
+
+// WrapTextFromAReaderWithEncoding is the same as WrapUTF8TextFromAReader(), but the text read from reader is
+// decoded using inputEncoding (for example, charmap.Windows1252, japanese.ShiftJIS or
+// unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)). If inputEncoding is nil, the text is UTF-8. Bytes that
+// are not valid in inputEncoding are decoded as U+FFFD, except that UTF-8 text (including text with a UTF-8 byte
+// order mark) is not decoded, so invalid bytes in it are handled according to the Wrapper's InvalidUTF8Policy. The
+// wrapped text is returned as a (UTF-8) string.
+func (wrapper *Wrapper) WrapTextFromAReaderWithEncoding(reader io.Reader, inputEncoding encoding.Encoding) (wrappedText string, err error) {
+	decodedReader, _, err := wrapper.decodingReaderFor(reader, inputEncoding)
+	if err != nil {
+		return "", err
+	}
+
+	return wrapper.WrapUTF8TextFromAReader(decodedReader)
+}
+
+// WrapEncodedTextFromAReader is the same as WrapTextFromAReaderWithEncoding(), but the wrapped text is encoded
+// using outputEncoding. If outputEncoding is nil, the wrapped text is encoded using the encoding of the input
+// (which, if a byte order mark was detected, is the encoding that it identifies, and the wrapped text then starts
+// with the same byte order mark). Characters that cannot be represented in the output encoding, including those
+// in the indent strings and the ellipsis, are replaced with the encoding's replacement character (for example, '?'
+// or 0x1A). If wrapping fails, the wrapped text up to the error is encoded and returned with the error.
+func (wrapper *Wrapper) WrapEncodedTextFromAReader(reader io.Reader, inputEncoding encoding.Encoding, outputEncoding encoding.Encoding) (wrappedText []byte, err error) {
+	decodedReader, encodingOfTheInput, err := wrapper.decodingReaderFor(reader, inputEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedUTF8Text, errorFromWrapping := wrapper.WrapUTF8TextFromAReader(decodedReader)
+
+	if outputEncoding == nil {
+		outputEncoding = encodingOfTheInput
+	}
+
+	var bufferOfEncodedText bytes.Buffer
+	encodingWriter := transform.NewWriter(&bufferOfEncodedText, encoding.ReplaceUnsupported(outputEncoding.NewEncoder()))
+
+	if _, err := io.WriteString(encodingWriter, wrappedUTF8Text); err != nil {
+		return nil, err
+	}
+
+	if err := encodingWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return bufferOfEncodedText.Bytes(), errorFromWrapping
+}
+
+// decodingReaderFor returns a reader that decodes the text read from reader into UTF-8, and the encoding that it
+// decodes, which is inputEncoding (or UTF-8 if inputEncoding is nil) unless a byte order mark is detected. UTF-8
+// text is returned without a decoder (other than removing the byte order mark), because the decoder would replace
+// invalid bytes with U+FFFD before the Wrapper's InvalidUTF8Policy could be applied to them.
+func (wrapper *Wrapper) decodingReaderFor(reader io.Reader, inputEncoding encoding.Encoding) (decodedReader io.Reader, encodingOfTheInput encoding.Encoding, err error) {
+	encodingOfTheInput = inputEncoding
+	if encodingOfTheInput == nil {
+		encodingOfTheInput = unicode.UTF8
+	}
+
+	if wrapper.detectByteOrderMark {
+		bufferedReader := bufio.NewReader(reader)
+
+		firstBytes, err := bufferedReader.Peek(3)
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+
+		if encodingOfTheByteOrderMark := encodingIdentifiedByByteOrderMarkAtTheStartOf(firstBytes); encodingOfTheByteOrderMark != nil {
+			encodingOfTheInput = encodingOfTheByteOrderMark
+		}
+
+		reader = bufferedReader
+	}
+
+	switch encodingOfTheInput {
+	case unicode.UTF8:
+		return reader, encodingOfTheInput, nil
+	case unicode.UTF8BOM:
+		return withoutAUTF8ByteOrderMark(reader), encodingOfTheInput, nil
+	}
+
+	return transform.NewReader(reader, encodingOfTheInput.NewDecoder()), encodingOfTheInput, nil
+}
+
+// withoutAUTF8ByteOrderMark returns a reader that reads the text read from reader, without the UTF-8 byte order
+// mark at its start, if there is one.
+func withoutAUTF8ByteOrderMark(reader io.Reader) io.Reader {
+	bufferedReader, isBuffered := reader.(*bufio.Reader)
+	if !isBuffered {
+		bufferedReader = bufio.NewReader(reader)
+	}
+
+	if firstBytes, _ := bufferedReader.Peek(3); bytes.HasPrefix(firstBytes, []byte{0xef, 0xbb, 0xbf}) {
+		bufferedReader.Discard(3)
+	}
+
+	return bufferedReader
+}
+
+// encodingIdentifiedByByteOrderMarkAtTheStartOf returns the encoding identified by the byte order mark at the start
+// of firstBytes, or nil if there is none. The returned encoding removes the byte order mark when decoding, and
+// writes it when encoding.
+func encodingIdentifiedByByteOrderMarkAtTheStartOf(firstBytes []byte) encoding.Encoding {
+	switch {
+	case bytes.HasPrefix(firstBytes, []byte{0xef, 0xbb, 0xbf}):
+		return unicode.UTF8BOM
+	case bytes.HasPrefix(firstBytes, []byte{0xfe, 0xff}):
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	case bytes.HasPrefix(firstBytes, []byte{0xff, 0xfe}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	default:
+		return nil
+	}
+}
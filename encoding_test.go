@@ -0,0 +1,233 @@
+package text_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+type EncodingTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	encodedInput          []byte
+	inputEncoding         encoding.Encoding
+	expectedWrappedString string
+	expectError           bool
+}
+
+func (testCase *EncodingTestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapTextFromAReaderWithEncoding(bytes.NewReader(testCase.encodedInput), testCase.inputEncoding)
+	if testCase.expectError && err == nil {
+		return fmt.Errorf("[%s] expected an error, got none", testCase.testName)
+	} else if !testCase.expectError && err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+type ReencodingTestCase struct {
+	testName            string
+	wrapper             *text.Wrapper
+	encodedInput        []byte
+	inputEncoding       encoding.Encoding
+	outputEncoding      encoding.Encoding
+	expectedWrappedText []byte
+	expectError         bool
+}
+
+func (testCase *ReencodingTestCase) RunTest() error {
+	wrappedText, err := testCase.wrapper.WrapEncodedTextFromAReader(bytes.NewReader(testCase.encodedInput), testCase.inputEncoding, testCase.outputEncoding)
+	if testCase.expectError && err == nil {
+		return fmt.Errorf("[%s] expected an error, got none", testCase.testName)
+	} else if !testCase.expectError && err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if !bytes.Equal(wrappedText, testCase.expectedWrappedText) {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedText, wrappedText)
+	}
+
+	return nil
+}
+
+func mustEncode(textEncoding encoding.Encoding, s string) []byte {
+	encodedBytes, err := textEncoding.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		panic(err)
+	}
+
+	return encodedBytes
+}
+
+func TestWrapTextFromAReaderWithEncoding(t *testing.T) {
+	utf16LittleEndianWithBOM := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+	utf16BigEndianWithBOM := unicode.UTF16(unicode.BigEndian, unicode.UseBOM)
+
+	testCases := []*EncodingTestCase{
+		{
+			testName:              "Latin-1",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			encodedInput:          []byte("caf\xe9 cr\xe8me br\xfbl\xe9e"),
+			inputEncoding:         charmap.ISO8859_1,
+			expectedWrappedString: "café crème\nbrûlée",
+		},
+		{
+			testName:              "Windows-1252 quotation marks",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			encodedInput:          []byte("\x93quoted\x94 text \x96 dash"),
+			inputEncoding:         charmap.Windows1252,
+			expectedWrappedString: "“quoted”\ntext –\ndash",
+		},
+		{
+			testName:              "Shift_JIS",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingMeasurer(text.EastAsianWideMeasurer),
+			encodedInput:          mustEncode(japanese.ShiftJIS, "日本語のテキスト"),
+			inputEncoding:         japanese.ShiftJIS,
+			expectedWrappedString: "日本語のテ\nキスト",
+		},
+		{
+			testName:              "UTF-16LE",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			encodedInput:          mustEncode(unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), "hello there world"),
+			inputEncoding:         unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+			expectedWrappedString: "hello\nthere\nworld",
+		},
+		{
+			testName:              "nil encoding is UTF-8",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			encodedInput:          []byte("café crème brûlée"),
+			inputEncoding:         nil,
+			expectedWrappedString: "café crème\nbrûlée",
+		},
+		{
+			testName:              "UTF-16BE byte order mark overrides the encoding",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingByteOrderMarkDetection(true),
+			encodedInput:          mustEncode(utf16BigEndianWithBOM, "hello there world"),
+			inputEncoding:         charmap.ISO8859_1,
+			expectedWrappedString: "hello\nthere\nworld",
+		},
+		{
+			testName:              "UTF-16LE byte order mark",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingByteOrderMarkDetection(true),
+			encodedInput:          mustEncode(utf16LittleEndianWithBOM, "hello there world"),
+			inputEncoding:         nil,
+			expectedWrappedString: "hello\nthere\nworld",
+		},
+		{
+			testName:              "UTF-8 byte order mark is removed",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingByteOrderMarkDetection(true),
+			encodedInput:          []byte("\xef\xbb\xbfhello there world"),
+			inputEncoding:         charmap.Windows1252,
+			expectedWrappedString: "hello\nthere\nworld",
+		},
+		{
+			testName:              "input without a byte order mark uses the encoding",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingByteOrderMarkDetection(true),
+			encodedInput:          []byte("caf\xe9"),
+			inputEncoding:         charmap.ISO8859_1,
+			expectedWrappedString: "café",
+		},
+		{
+			testName:              "invalid UTF-8 fails",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			encodedInput:          []byte("hello there \xff world"),
+			inputEncoding:         nil,
+			expectedWrappedString: "hello\nthere",
+			expectError:           true,
+		},
+		{
+			testName:              "invalid UTF-8 fails after a byte order mark",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingByteOrderMarkDetection(true),
+			encodedInput:          []byte("\xef\xbb\xbfhello there \xff world"),
+			inputEncoding:         charmap.Windows1252,
+			expectedWrappedString: "hello\nthere",
+			expectError:           true,
+		},
+		{
+			testName:              "invalid UTF-8 passed through",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingInvalidUTF8Policy(text.PassInvalidUTF8Through),
+			encodedInput:          []byte("hello \xff world"),
+			inputEncoding:         unicode.UTF8,
+			expectedWrappedString: "hello \xff\nworld",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestWrapEncodedTextFromAReader(t *testing.T) {
+	utf16LittleEndianWithBOM := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+
+	testCases := []*ReencodingTestCase{
+		{
+			testName:            "re-encoded to the input encoding",
+			wrapper:             text.NewWrapper().UsingRowWidth(10),
+			encodedInput:        []byte("caf\xe9 cr\xe8me br\xfbl\xe9e"),
+			inputEncoding:       charmap.ISO8859_1,
+			outputEncoding:      nil,
+			expectedWrappedText: []byte("caf\xe9 cr\xe8me\nbr\xfbl\xe9e"),
+		},
+		{
+			testName:            "re-encoded to another encoding",
+			wrapper:             text.NewWrapper().UsingRowWidth(10),
+			encodedInput:        []byte("caf\xe9 cr\xe8me br\xfbl\xe9e"),
+			inputEncoding:       charmap.ISO8859_1,
+			outputEncoding:      unicode.UTF8,
+			expectedWrappedText: []byte("café crème\nbrûlée"),
+		},
+		{
+			testName:            "re-encoded to the encoding of the byte order mark",
+			wrapper:             text.NewWrapper().UsingRowWidth(10).UsingByteOrderMarkDetection(true),
+			encodedInput:        mustEncode(utf16LittleEndianWithBOM, "hello there world"),
+			inputEncoding:       nil,
+			outputEncoding:      nil,
+			expectedWrappedText: mustEncode(utf16LittleEndianWithBOM, "hello\nthere\nworld"),
+		},
+		{
+			testName:            "Shift_JIS to UTF-8",
+			wrapper:             text.NewWrapper().UsingRowWidth(10).UsingMeasurer(text.EastAsianWideMeasurer),
+			encodedInput:        mustEncode(japanese.ShiftJIS, "日本語のテキスト"),
+			inputEncoding:       japanese.ShiftJIS,
+			outputEncoding:      unicode.UTF8,
+			expectedWrappedText: []byte("日本語のテ\nキスト"),
+		},
+		{
+			testName:            "unrepresentable ellipsis is replaced",
+			wrapper:             text.NewWrapper().UsingRowWidth(10).UsingMaximumRows(1),
+			encodedInput:        []byte("caf\xe9 cr\xe8me br\xfbl\xe9e"),
+			inputEncoding:       charmap.ISO8859_1,
+			outputEncoding:      nil,
+			expectedWrappedText: []byte("caf\xe9\x1a"),
+		},
+		{
+			testName:            "wrapped text up to an error is returned",
+			wrapper:             text.NewWrapper().UsingRowWidth(10).UsingOutputRowLimit(1),
+			encodedInput:        []byte("caf\xe9 cr\xe8me br\xfbl\xe9e"),
+			inputEncoding:       charmap.ISO8859_1,
+			outputEncoding:      nil,
+			expectedWrappedText: []byte("caf\xe9 cr\xe8me"),
+			expectError:         true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
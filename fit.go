@@ -0,0 +1,126 @@
+package text
+
+import (
+	"strings"
+)
+
+// Alignment is the horizontal placement of text within a space that is wider than the text.
+type Alignment int
+
+const (
+	// AlignLeft places text at the start of the space, padding on the right.
+	AlignLeft Alignment = iota
+
+	// AlignRight places text at the end of the space, padding on the left.
+	AlignRight
+
+	// AlignCenter places text in the middle of the space, padding on both sides. If the padding cannot
+	// be split evenly, the extra space goes on the right.
+	AlignCenter
+)
+
+// StringWidth returns the number of columns that a Wrapper using the default Measurer (RuneCountMeasurer) counts
+// for the provided string. Truncate(), Pad() and Fit() (and the functions that they use) all measure text with
+// StringWidth(), so text that they pad or truncate lines up with text wrapped by a default Wrapper. A Wrapper with a
+// different Measurer has methods of the same names that measure text with its Measurer. ColumnLayout, Table and
+// HelpFormatter measure text with the Measurer of the Wrapper that they use.
+func StringWidth(s string) int {
+	return widthOfStringUsing(measurerAdvancer{measurer: RuneCountMeasurer}, s)
+}
+
+// Truncate returns s unchanged if it is no wider than width. Otherwise, it returns the start of s followed by
+// ellipsis, together exactly width columns wide (or narrower, if a character and the combining marks that follow
+// it must be kept together). If ellipsis is wider than width, the ellipsis itself is truncated.
+func Truncate(s string, width uint, ellipsis string) string {
+	return truncateUsing(measurerAdvancer{measurer: RuneCountMeasurer}, s, width, ellipsis)
+}
+
+// PadRight returns s followed by enough spaces to make it width columns wide. If s is already at least width
+// columns wide, it is returned unchanged.
+func PadRight(s string, width uint) string {
+	return s + spacesToPad(measurerAdvancer{measurer: RuneCountMeasurer}, s, width)
+}
+
+// PadLeft returns s preceded by enough spaces to make it width columns wide. If s is already at least width
+// columns wide, it is returned unchanged.
+func PadLeft(s string, width uint) string {
+	return spacesToPad(measurerAdvancer{measurer: RuneCountMeasurer}, s, width) + s
+}
+
+// Center returns s with spaces on both sides to make it width columns wide. If the number of spaces is odd,
+// the extra space is placed on the right. If s is already at least width columns wide, it is returned unchanged.
+func Center(s string, width uint) string {
+	return centerUsing(measurerAdvancer{measurer: RuneCountMeasurer}, s, width)
+}
+
+// Pad returns s padded to width columns, using PadRight() for AlignLeft, PadLeft() for AlignRight and Center()
+// for AlignCenter.
+func Pad(s string, width uint, alignment Alignment) string {
+	return padUsing(measurerAdvancer{measurer: RuneCountMeasurer}, s, width, alignment)
+}
+
+// Fit returns s as exactly width columns. If s is too wide, it is truncated with Truncate(). If it is too
+// narrow, it is padded with Pad(). If truncation must leave a column empty to keep combining marks with their
+// character (or because a wide character does not fit), the result is padded to width after truncation.
+func Fit(s string, width uint, alignment Alignment, ellipsis string) string {
+	return padUsing(measurerAdvancer{measurer: RuneCountMeasurer}, truncateUsing(measurerAdvancer{measurer: RuneCountMeasurer}, s, width, ellipsis), width, alignment)
+}
+
+// StringWidth is the same as the package function StringWidth(), but measures s with the Wrapper Measurer.
+func (wrapper *Wrapper) StringWidth(s string) int {
+	return widthOfStringUsing(wrapper.columnAdvancer(), s)
+}
+
+// Truncate is the same as the package function Truncate(), but measures s and ellipsis with the Wrapper Measurer.
+func (wrapper *Wrapper) Truncate(s string, width uint, ellipsis string) string {
+	return truncateUsing(wrapper.columnAdvancer(), s, width, ellipsis)
+}
+
+// Pad is the same as the package function Pad(), but measures s with the Wrapper Measurer.
+func (wrapper *Wrapper) Pad(s string, width uint, alignment Alignment) string {
+	return padUsing(wrapper.columnAdvancer(), s, width, alignment)
+}
+
+// Fit is the same as the package function Fit(), but measures s and ellipsis with the Wrapper Measurer.
+func (wrapper *Wrapper) Fit(s string, width uint, alignment Alignment, ellipsis string) string {
+	return padUsing(wrapper.columnAdvancer(), truncateUsing(wrapper.columnAdvancer(), s, width, ellipsis), width, alignment)
+}
+
+func truncateUsing(advancer runeAdvancer, s string, width uint, ellipsis string) string {
+	if widthOfStringUsing(advancer, s) <= int(width) {
+		return s
+	}
+
+	ellipsisRunes := []rune(ellipsis)
+	widthOfEllipsis := widthOfRunesUsing(advancer, ellipsisRunes)
+	if widthOfEllipsis >= int(width) {
+		return string(runesBeforeCharacterAt(advancer, ellipsisRunes, int(width)))
+	}
+
+	return string(runesBeforeCharacterAt(advancer, []rune(s), int(width)-widthOfEllipsis)) + ellipsis
+}
+
+func centerUsing(advancer runeAdvancer, s string, width uint) string {
+	padding := spacesToPad(advancer, s, width)
+	return padding[:len(padding)/2] + s + padding[len(padding)/2:]
+}
+
+func padUsing(advancer runeAdvancer, s string, width uint, alignment Alignment) string {
+	switch alignment {
+	case AlignRight:
+		return spacesToPad(advancer, s, width) + s
+	case AlignCenter:
+		return centerUsing(advancer, s, width)
+	default:
+		return s + spacesToPad(advancer, s, width)
+	}
+}
+
+func spacesToPad(advancer runeAdvancer, s string, width uint) string {
+	paddingWidth := int(width) - widthOfStringUsing(advancer, s)
+	if paddingWidth <= 0 {
+		return ""
+	}
+
+	return strings.Repeat(" ", paddingWidth/advancer.advanceOf(noPreviousRune, ' '))
+}
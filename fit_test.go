@@ -0,0 +1,56 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type FitTestCase struct {
+	testName       string
+	function       func() string
+	expectedString string
+}
+
+func (testCase *FitTestCase) RunTest() error {
+	if gotString := testCase.function(); gotString != testCase.expectedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedString, gotString)
+	}
+
+	return nil
+}
+
+func TestStringWidth(t *testing.T) {
+	for testString, expectedWidth := range map[string]int{"": 0, "abc": 3, "∀∁∂∃ ∄": 6, "ḂϞ": 2} {
+		if width := text.StringWidth(testString); width != expectedWidth {
+			t.Errorf("for (%q) expected StringWidth() = (%d), got = (%d)", testString, expectedWidth, width)
+		}
+	}
+}
+
+func TestFitFunctions(t *testing.T) {
+	testCases := []*FitTestCase{
+		{"Truncate shorter string", func() string { return text.Truncate("abc", 5, "…") }, "abc"},
+		{"Truncate exact width string", func() string { return text.Truncate("abcde", 5, "…") }, "abcde"},
+		{"Truncate wider string", func() string { return text.Truncate("abcdef", 5, "…") }, "abcd…"},
+		{"Truncate multibyte string", func() string { return text.Truncate("∀∁∂∃∄∅∆", 5, "...") }, "∀∁..."},
+		{"Truncate keeps combining mark", func() string { return text.Truncate("abce\u0301fg", 5, "…") }, "abc…"},
+		{"Truncate with wide ellipsis", func() string { return text.Truncate("abcdef", 2, "...") }, ".."},
+		{"PadRight", func() string { return text.PadRight("∀∁", 5) }, "∀∁   "},
+		{"PadRight wider string", func() string { return text.PadRight("abcdef", 5) }, "abcdef"},
+		{"PadLeft", func() string { return text.PadLeft("∀∁", 5) }, "   ∀∁"},
+		{"Center", func() string { return text.Center("∀∁", 5) }, " ∀∁  "},
+		{"Center even padding", func() string { return text.Center("ab", 6) }, "  ab  "},
+		{"Pad right aligned", func() string { return text.Pad("ab", 4, text.AlignRight) }, "  ab"},
+		{"Fit narrower string", func() string { return text.Fit("ab", 4, text.AlignCenter, "…") }, " ab "},
+		{"Fit wider string", func() string { return text.Fit("abcdef", 4, text.AlignLeft, "…") }, "abc…"},
+		{"Fit keeps combining mark", func() string { return text.Fit("abce\u0301fg", 5, text.AlignLeft, "…") }, "abc… "},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
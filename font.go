@@ -0,0 +1,215 @@
+package text
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+
+	"golang.org/x/image/font"
+)
+
+// fontUnitsPerPoint is the resolution of the width accounting used when a Wrapper has FontMetrics. Widths are
+// rounded to 1/64 of a point, which is the resolution of the fixed.Int26_6 values used by golang.org/x/image/font.
+const fontUnitsPerPoint = 64
+
+// FontMetrics supplies the glyph widths of a proportional font. Widths may be in any unit (for example, points or
+// pixels), as long as the row width passed to ChangeFontMetricsTo() is in the same unit. This package calls the
+// unit a "point".
+type FontMetrics interface {
+	// GlyphAdvance returns the advance width of the glyph for r.
+	GlyphAdvance(r rune) float64
+
+	// Kerning returns the adjustment to the advance width of the glyph for r when it follows the glyph for
+	// previousRune. It is usually 0 or negative.
+	Kerning(previousRune rune, r rune) float64
+}
+
+type kerningPair struct {
+	previousRune rune
+	r            rune
+}
+
+// FontMetricsTable is a FontMetrics built from a table of advance widths and kerning pairs, for example from the
+// AFM file of a PDF base font.
+type FontMetricsTable struct {
+	defaultAdvanceWidth float64
+	advanceWidths       map[rune]float64
+	kerningAdjustments  map[kerningPair]float64
+}
+
+// NewFontMetricsTable creates a FontMetricsTable with no advance widths and no kerning pairs. Runes that are not
+// in the table have the provided default advance width.
+func NewFontMetricsTable(defaultAdvanceWidth float64) *FontMetricsTable {
+	return &FontMetricsTable{
+		defaultAdvanceWidth: defaultAdvanceWidth,
+		advanceWidths:       make(map[rune]float64),
+		kerningAdjustments:  make(map[kerningPair]float64),
+	}
+}
+
+// UsingAdvanceWidth sets the advance width of the glyph for r.
+func (table *FontMetricsTable) UsingAdvanceWidth(r rune, width float64) *FontMetricsTable {
+	table.advanceWidths[r] = width
+	return table
+}
+
+// UsingAdvanceWidths sets the advance widths of the glyphs for each rune in the provided map.
+func (table *FontMetricsTable) UsingAdvanceWidths(widths map[rune]float64) *FontMetricsTable {
+	for r, width := range widths {
+		table.advanceWidths[r] = width
+	}
+
+	return table
+}
+
+// UsingKerning sets the adjustment to the advance width of the glyph for r when it follows the glyph for
+// previousRune.
+func (table *FontMetricsTable) UsingKerning(previousRune rune, r rune, adjustment float64) *FontMetricsTable {
+	table.kerningAdjustments[kerningPair{previousRune: previousRune, r: r}] = adjustment
+	return table
+}
+
+// GlyphAdvance returns the advance width set for r, or the default advance width if none was set.
+func (table *FontMetricsTable) GlyphAdvance(r rune) float64 {
+	if width, isInTheTable := table.advanceWidths[r]; isInTheTable {
+		return width
+	}
+
+	return table.defaultAdvanceWidth
+}
+
+// Kerning returns the adjustment set for the pair, or 0 if none was set.
+func (table *FontMetricsTable) Kerning(previousRune rune, r rune) float64 {
+	return table.kerningAdjustments[kerningPair{previousRune: previousRune, r: r}]
+}
+
+type fontFaceMetrics struct {
+	face font.Face
+}
+
+// NewFontFaceMetrics returns FontMetrics that take advance widths and kerning from a golang.org/x/image/font Face,
+// such as one created from a parsed TrueType or OpenType font (whose advance widths come from the font's hmtx
+// table) with golang.org/x/image/font/opentype. Widths are in the units of the Face, which are pixels at the DPI
+// used to create it (so they are points if the DPI is 72). A rune for which the Face has no glyph uses the advance
+// width of the Face's replacement glyph (U+FFFD), if it has one.
+func NewFontFaceMetrics(face font.Face) FontMetrics {
+	return &fontFaceMetrics{face: face}
+}
+
+func (metrics *fontFaceMetrics) GlyphAdvance(r rune) float64 {
+	advance, glyphIsInTheFace := metrics.face.GlyphAdvance(r)
+	if !glyphIsInTheFace {
+		advance, _ = metrics.face.GlyphAdvance(unicode.ReplacementChar)
+	}
+
+	return float64(advance) / fontUnitsPerPoint
+}
+
+func (metrics *fontFaceMetrics) Kerning(previousRune rune, r rune) float64 {
+	return float64(metrics.face.Kern(previousRune, r)) / fontUnitsPerPoint
+}
+
+// fontMetricsAdvancer uses FontMetrics for width accounting. Each whitespace rune is measured as an ASCII space,
+// because that is how it is emitted in wrapped text, and each invalid byte that is passed through is measured as
+// U+FFFD.
+type fontMetricsAdvancer struct {
+	metrics FontMetrics
+}
+
+func (advancer fontMetricsAdvancer) advanceOf(previousRune rune, r rune) int {
+	if unicode.IsSpace(r) {
+		r = ' '
+	} else if isRawByteRune(r) {
+		r = unicode.ReplacementChar
+	}
+
+	advance := advancer.metrics.GlyphAdvance(r)
+
+	if previousRune != noPreviousRune {
+		if unicode.IsSpace(previousRune) {
+			previousRune = ' '
+		}
+
+		advance += advancer.metrics.Kerning(previousRune, r)
+	}
+
+	return int(math.Round(advance * fontUnitsPerPoint))
+}
+
+// ChangeFontMetricsTo makes the Wrapper measure text with the metrics of a proportional font rather than in
+// columns. The row width is a number of points (in the unit used by the FontMetrics), and replaces the current row
+// width. The advance width of every glyph (including the indent strings, the ellipsis and the ASCII space emitted
+// for whitespace), adjusted by the kerning with the glyph before it in the row, counts against the row width.
+// Widths are rounded to 1/64 of a point. While a Wrapper has FontMetrics, ChangeRowWidthTo() also sets the row width
+// in points. The Measurer is not used for wrapping, but it is still used by StringWidth(), Truncate(), Pad() and
+// Fit(), and by ColumnLayout, Table and HelpFormatter, which lay out text in columns and ignore the FontMetrics.
+//
+// If metrics is nil, the Wrapper goes back to measuring text in columns with its Measurer, and the row width is set
+// to rowWidth columns (rounded down). It panics if the row width is not larger than the width of the indent strings.
+func (wrapper *Wrapper) ChangeFontMetricsTo(metrics FontMetrics, rowWidth float64) *Wrapper {
+	resizedWrapper := *wrapper
+	if err := resizedWrapper.changeRowWidthWithFontMetricsTo(metrics, rowWidth); err != nil {
+		panic("RowWidth must be larger than row indent string")
+	}
+
+	*wrapper = resizedWrapper
+	return wrapper
+}
+
+// UsingFontMetrics is the same as ChangeFontMetricsTo(), but provides a more readable name if this is chained with
+// the constructor, as in:
+//    wrapper := text.NewWrapper().UsingFontMetrics(text.NewFontFaceMetrics(face), 451.5)
+func (wrapper *Wrapper) UsingFontMetrics(metrics FontMetrics, rowWidth float64) *Wrapper {
+	return wrapper.ChangeFontMetricsTo(metrics, rowWidth)
+}
+
+// changeRowWidthWithFontMetricsTo sets the FontMetrics and the row width, which is a number of points if metrics
+// is not nil, and a number of columns otherwise. It returns an error that wraps ErrRowWidthTooSmall if rowWidth is
+// negative or is not larger than the width of an indent string, in which case the Wrapper is left in a state that
+// must not be used.
+func (wrapper *Wrapper) changeRowWidthWithFontMetricsTo(metrics FontMetrics, rowWidth float64) error {
+	if rowWidth < 0 {
+		return fmt.Errorf("%w: row width (%g) is negative", ErrRowWidthTooSmall, rowWidth)
+	}
+
+	wrapper.fontMetrics = metrics
+	if metrics != nil {
+		wrapper.fontRowWidth = uint(math.Floor(rowWidth * fontUnitsPerPoint))
+	} else {
+		wrapper.columnsPerRow = uint(math.Floor(rowWidth))
+	}
+
+	for _, indent := range []struct {
+		name  string
+		runes []rune
+	}{
+		{"first row indent string", wrapper.initialLineIndentString},
+		{"indent string for rows after the first", wrapper.subsequentLinesIndentString},
+	} {
+		if widthOfIndent := wrapper.widthOfRunes(indent.runes); widthOfIndent >= int(wrapper.rowWidthInWidthUnits()) {
+			return fmt.Errorf("%w: row width (%g) is not larger than the %s (%q)", ErrRowWidthTooSmall, rowWidth, indent.name, string(indent.runes))
+		}
+	}
+
+	return nil
+}
+
+// rowWidthInWidthUnits is the row width in the units of width accounting, which are columns, or with FontMetrics,
+// 1/64 of a point.
+func (wrapper *Wrapper) rowWidthInWidthUnits() uint {
+	if wrapper.fontMetrics != nil {
+		return wrapper.fontRowWidth
+	}
+
+	return wrapper.columnsPerRow
+}
+
+// runesInARow is the number of runes that the buffers used by the wrapping engine are sized for. With FontMetrics,
+// each rune is taken to be one point wide, which is narrower than the glyphs of most fonts at text sizes.
+func (wrapper *Wrapper) runesInARow() uint {
+	if wrapper.fontMetrics != nil {
+		return wrapper.fontRowWidth / fontUnitsPerPoint
+	}
+
+	return wrapper.columnsPerRow
+}
@@ -0,0 +1,206 @@
+package text_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+type FontMetricsTestCase struct {
+	testName              string
+	metrics               text.FontMetrics
+	rowWidth              float64
+	firstRowIndentString  string
+	maximumRows           uint
+	unwrappedString       string
+	expectedWrappedString string
+}
+
+func (testCase *FontMetricsTestCase) RunTest() error {
+	wrapper := text.NewWrapper().
+		UsingIndentStringForFirstRow(testCase.firstRowIndentString).
+		UsingFontMetrics(testCase.metrics, testCase.rowWidth).
+		UsingMaximumRows(testCase.maximumRows)
+
+	wrappedString, err := wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestWrapUsingFontMetrics(t *testing.T) {
+	metricsTable := text.NewFontMetricsTable(10).
+		UsingAdvanceWidths(map[rune]float64{'i': 4, 'm': 15, ' ': 5, 'a': 6.5, '…': 8})
+
+	kernedMetricsTable := text.NewFontMetricsTable(10).
+		UsingAdvanceWidth(' ', 5).
+		UsingKerning('A', 'V', -3)
+
+	testCases := []*FontMetricsTestCase{
+		{
+			testName:              "advance widths from a table",
+			metrics:               metricsTable,
+			rowWidth:              50,
+			unwrappedString:       "mmm iii mm",
+			expectedWrappedString: "mmm\niii mm",
+		},
+		{
+			testName:              "fractional row width that fits",
+			metrics:               metricsTable,
+			rowWidth:              19.5,
+			unwrappedString:       "aaaa",
+			expectedWrappedString: "aaa\na",
+		},
+		{
+			testName:              "fractional row width that does not fit",
+			metrics:               metricsTable,
+			rowWidth:              19.4,
+			unwrappedString:       "aaaa",
+			expectedWrappedString: "aa\naa",
+		},
+		{
+			testName:              "indent counts against the row width",
+			metrics:               metricsTable,
+			rowWidth:              50,
+			firstRowIndentString:  "  ",
+			unwrappedString:       "mm mm",
+			expectedWrappedString: "  mm\nmm",
+		},
+		{
+			testName:              "without kerning",
+			metrics:               kernedMetricsTable,
+			rowWidth:              17,
+			unwrappedString:       "VA VA",
+			expectedWrappedString: "V\nA\nV\nA",
+		},
+		{
+			testName:              "with kerning",
+			metrics:               kernedMetricsTable,
+			rowWidth:              17,
+			unwrappedString:       "AV AV",
+			expectedWrappedString: "AV\nAV",
+		},
+		{
+			testName:              "truncation measures the ellipsis",
+			metrics:               metricsTable,
+			rowWidth:              50,
+			maximumRows:           1,
+			unwrappedString:       "iii mmm iii",
+			expectedWrappedString: "iii…",
+		},
+		{
+			testName:              "basicfont face",
+			metrics:               text.NewFontFaceMetrics(basicfont.Face7x13),
+			rowWidth:              35,
+			unwrappedString:       "hello world",
+			expectedWrappedString: "hello\nworld",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestWrapUsingOpenTypeFontFace(t *testing.T) {
+	parsedFont, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %s", err)
+	}
+
+	face, err := opentype.NewFace(parsedFont, &opentype.FaceOptions{Size: 12, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		t.Fatalf("failed to create face: %s", err)
+	}
+
+	rowWidth := 180.0
+	unwrappedString := "We render wrapped text into PNG and PDF reports with a proportional font, and character counts " +
+		"are useless there. Wide letters like W and M take more room than narrow ones like i, l and t."
+
+	wrappedString, err := text.NewWrapper().UsingFontMetrics(text.NewFontFaceMetrics(face), rowWidth).WrapStringText(unwrappedString)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wrappedRows := strings.Split(wrappedString, "\n")
+	if strings.Join(wrappedRows, " ") != unwrappedString {
+		t.Errorf("expected rows to rejoin into the unwrapped string, got = (%q)", wrappedString)
+	}
+
+	for rowIndex, row := range wrappedRows {
+		if rowWidthInFontUnits := font.MeasureString(face, row); rowWidthInFontUnits > fixed.I(int(rowWidth)) {
+			t.Errorf("row %d (%q) is wider than %.1f points: %s", rowIndex+1, row, rowWidth, rowWidthInFontUnits)
+		}
+
+		if rowIndex+1 < len(wrappedRows) {
+			rowWithNextWord := row + " " + strings.Fields(wrappedRows[rowIndex+1])[0]
+			if font.MeasureString(face, rowWithNextWord) <= fixed.I(int(rowWidth)) {
+				t.Errorf("row %d (%q) could have included the next word", rowIndex+1, row)
+			}
+		}
+	}
+}
+
+func TestChangeFontMetricsPanicsWhenIndentDoesNotFit(t *testing.T) {
+	wrapper := text.NewWrapper().UsingIndentStringForRowsAfterTheFirst("    ")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected ChangeFontMetricsTo() to panic, but it did not")
+		}
+	}()
+
+	wrapper.ChangeFontMetricsTo(text.NewFontMetricsTable(10), 40)
+}
+
+func TestRowWidthInPointsAndInColumns(t *testing.T) {
+	wrapper := text.NewWrapper().UsingRowWidth(12).UsingFontMetrics(text.NewFontMetricsTable(5), 40)
+
+	for _, step := range []struct {
+		testName              string
+		change                func()
+		expectedWrappedString string
+	}{
+		{
+			testName:              "row width in points from ChangeFontMetricsTo()",
+			change:                func() {},
+			expectedWrappedString: "aaaa\nbbbb\ncccc",
+		},
+		{
+			testName:              "row width in points from ChangeRowWidthTo()",
+			change:                func() { wrapper.ChangeRowWidthTo(50) },
+			expectedWrappedString: "aaaa bbbb\ncccc",
+		},
+		{
+			testName:              "row width in columns after the font metrics are removed",
+			change:                func() { wrapper.ChangeFontMetricsTo(nil, 14) },
+			expectedWrappedString: "aaaa bbbb cccc",
+		},
+		{
+			testName:              "row width in columns from ChangeRowWidthTo()",
+			change:                func() { wrapper.ChangeRowWidthTo(9) },
+			expectedWrappedString: "aaaa bbbb\ncccc",
+		},
+	} {
+		step.change()
+
+		if wrappedString := wrapper.MustWrapStringText("aaaa bbbb cccc"); wrappedString != step.expectedWrappedString {
+			t.Errorf("[%s] expected = (%q), got = (%q)", step.testName, step.expectedWrappedString, wrappedString)
+		}
+	}
+}
@@ -2,4 +2,8 @@ module github.com/blorticus-go/text
 
 go 1.17
 
-require github.com/blorticus-go/nibblers v0.6.1
+require (
+	github.com/blorticus-go/nibblers v0.6.1
+	golang.org/x/image v0.12.0
+	golang.org/x/text v0.13.0
+)
@@ -0,0 +1,203 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+)
+
+type helpEntry struct {
+	term       string
+	definition string
+}
+
+// HelpFormatter formats a list of terms and their definitions (for example, command-line flags and their
+// descriptions) in the layout used by GNU getopt/argp help output:
+//      -o, --output=FILE      Write the output to FILE instead of standard output,
+//                             creating it if needed
+//          --a-very-long-option-name
+//                             Definitions for terms that are too wide start on the
+//                             next row
+// Each term is preceded by a (configurable) indent. Definitions start at the definition column. If the
+// definition column is not set, it is computed from the widest term that leaves a (configurable) minimum
+// gap before the maximum definition column. Definitions are wrapped using a Wrapper, with a hanging indent
+// that aligns each wrapped row with the definition column. If a term (plus its indent and the minimum gap)
+// is wider than the definition column, the term is placed on a row by itself and the definition begins on
+// the following row.
+type HelpFormatter struct {
+	entries                 []*helpEntry
+	rowWidth                uint
+	termIndentString        string
+	definitionColumn        uint
+	maximumDefinitionColumn uint
+	minimumGapAfterTerm     uint
+	measurer                Measurer
+}
+
+// NewHelpFormatter creates a HelpFormatter with no entries. By default, the row width is 79, the term indent
+// is two spaces, the definition column is computed with a maximum of 30, and the minimum gap after a term is
+// two columns. Terms and definitions are measured with RuneCountMeasurer.
+func NewHelpFormatter() *HelpFormatter {
+	return &HelpFormatter{
+		entries:                 make([]*helpEntry, 0, 10),
+		rowWidth:                79,
+		termIndentString:        "  ",
+		definitionColumn:        0,
+		maximumDefinitionColumn: 30,
+		minimumGapAfterTerm:     2,
+		measurer:                RuneCountMeasurer,
+	}
+}
+
+// ChangeRowWidthTo changes the width of each row of formatted help text.
+func (formatter *HelpFormatter) ChangeRowWidthTo(numberOfColumns uint) *HelpFormatter {
+	formatter.rowWidth = numberOfColumns
+	return formatter
+}
+
+// UsingRowWidth is the same as ChangeRowWidthTo(), but provides a more readable name if this is chained with
+// the constructor.
+func (formatter *HelpFormatter) UsingRowWidth(numberOfColumns uint) *HelpFormatter {
+	return formatter.ChangeRowWidthTo(numberOfColumns)
+}
+
+// ChangeTermIndentStringTo changes the string placed before each term.
+func (formatter *HelpFormatter) ChangeTermIndentStringTo(indent string) *HelpFormatter {
+	formatter.termIndentString = indent
+	return formatter
+}
+
+// UsingTermIndentString is the same as ChangeTermIndentStringTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (formatter *HelpFormatter) UsingTermIndentString(indent string) *HelpFormatter {
+	return formatter.ChangeTermIndentStringTo(indent)
+}
+
+// ChangeDefinitionColumnTo fixes the column (counting from 0) at which definitions start. A value of 0 means the
+// definition column is computed from the terms.
+func (formatter *HelpFormatter) ChangeDefinitionColumnTo(column uint) *HelpFormatter {
+	formatter.definitionColumn = column
+	return formatter
+}
+
+// UsingDefinitionColumn is the same as ChangeDefinitionColumnTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (formatter *HelpFormatter) UsingDefinitionColumn(column uint) *HelpFormatter {
+	return formatter.ChangeDefinitionColumnTo(column)
+}
+
+// ChangeMaximumDefinitionColumnTo changes the largest column that may be computed for the start of definitions.
+// It has no effect if the definition column is fixed.
+func (formatter *HelpFormatter) ChangeMaximumDefinitionColumnTo(column uint) *HelpFormatter {
+	formatter.maximumDefinitionColumn = column
+	return formatter
+}
+
+// UsingMaximumDefinitionColumn is the same as ChangeMaximumDefinitionColumnTo(), but provides a more readable
+// name if this is chained with the constructor.
+func (formatter *HelpFormatter) UsingMaximumDefinitionColumn(column uint) *HelpFormatter {
+	return formatter.ChangeMaximumDefinitionColumnTo(column)
+}
+
+// ChangeMinimumGapAfterTermTo changes the smallest number of columns between the end of a term and the start of
+// its definition on the same row.
+func (formatter *HelpFormatter) ChangeMinimumGapAfterTermTo(numberOfColumns uint) *HelpFormatter {
+	formatter.minimumGapAfterTerm = numberOfColumns
+	return formatter
+}
+
+// UsingMinimumGapAfterTerm is the same as ChangeMinimumGapAfterTermTo(), but provides a more readable name if this
+// is chained with the constructor.
+func (formatter *HelpFormatter) UsingMinimumGapAfterTerm(numberOfColumns uint) *HelpFormatter {
+	return formatter.ChangeMinimumGapAfterTermTo(numberOfColumns)
+}
+
+// ChangeMeasurerTo changes the Measurer used to measure terms and to wrap definitions.
+func (formatter *HelpFormatter) ChangeMeasurerTo(measurer Measurer) *HelpFormatter {
+	if measurer == nil {
+		panic("Measurer must not be nil")
+	}
+
+	formatter.measurer = measurer
+	return formatter
+}
+
+// UsingMeasurer is the same as ChangeMeasurerTo(), but provides a more readable name if this is chained with the
+// constructor.
+func (formatter *HelpFormatter) UsingMeasurer(measurer Measurer) *HelpFormatter {
+	return formatter.ChangeMeasurerTo(measurer)
+}
+
+// AppendEntry adds a term and its definition to the end of the help text. The definition may be empty.
+func (formatter *HelpFormatter) AppendEntry(term string, definition string) *HelpFormatter {
+	formatter.entries = append(formatter.entries, &helpEntry{term: term, definition: definition})
+	return formatter
+}
+
+// DefinitionColumn returns the column at which definitions start. This is either the fixed definition column
+// or the column computed from the terms.
+func (formatter *HelpFormatter) DefinitionColumn() uint {
+	if formatter.definitionColumn > 0 {
+		return formatter.definitionColumn
+	}
+
+	computedDefinitionColumn := 0
+	for _, entry := range formatter.entries {
+		columnAfterTermAndGap := formatter.widthOfIndentedTerm(entry) + int(formatter.minimumGapAfterTerm)
+		if columnAfterTermAndGap <= int(formatter.maximumDefinitionColumn) && columnAfterTermAndGap > computedDefinitionColumn {
+			computedDefinitionColumn = columnAfterTermAndGap
+		}
+	}
+
+	if computedDefinitionColumn == 0 {
+		return formatter.maximumDefinitionColumn
+	}
+
+	return uint(computedDefinitionColumn)
+}
+
+func (formatter *HelpFormatter) widthOfIndentedTerm(entry *helpEntry) int {
+	return widthOfStringUsing(measurerAdvancer{measurer: formatter.measurer}, formatter.termIndentString+entry.term)
+}
+
+// Format returns the formatted help text, with each entry starting on a new row. Rows are separated by a newline,
+// and there is no newline after the last row. It returns an error if the definition column leaves no room for
+// definitions in the row width, or if wrapping fails.
+func (formatter *HelpFormatter) Format() (formattedHelp string, err error) {
+	definitionColumn := formatter.DefinitionColumn()
+	if definitionColumn >= formatter.rowWidth {
+		return "", fmt.Errorf("definition column (%d) must be less than row width (%d)", definitionColumn, formatter.rowWidth)
+	}
+
+	definitionIndent := strings.Repeat(" ", int(definitionColumn))
+	formattedEntries := make([]string, 0, len(formatter.entries))
+
+	for _, entry := range formatter.entries {
+		indentedTerm := formatter.termIndentString + entry.term
+
+		wrapperForDefinition := NewWrapper()
+		wrapperForDefinition.columnsPerRow = formatter.rowWidth
+		wrapperForDefinition.measurer = formatter.measurer
+		wrapperForDefinition.subsequentLinesIndentString = []rune(definitionIndent)
+
+		rowsBeforeDefinition := ""
+		if formatter.widthOfIndentedTerm(entry)+int(formatter.minimumGapAfterTerm) <= int(definitionColumn) {
+			wrapperForDefinition.initialLineIndentString = []rune(wrapperForDefinition.Pad(indentedTerm, definitionColumn, AlignLeft))
+		} else {
+			rowsBeforeDefinition = indentedTerm + "\n"
+			wrapperForDefinition.initialLineIndentString = []rune(definitionIndent)
+		}
+
+		wrappedDefinition, err := wrapperForDefinition.WrapStringText(entry.definition)
+		if err != nil {
+			return "", err
+		}
+
+		if wrappedDefinition == "" {
+			formattedEntries = append(formattedEntries, indentedTerm)
+		} else {
+			formattedEntries = append(formattedEntries, rowsBeforeDefinition+wrappedDefinition)
+		}
+	}
+
+	return strings.Join(formattedEntries, "\n"), nil
+}
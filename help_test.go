@@ -0,0 +1,106 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type HelpFormatterTestCase struct {
+	testName              string
+	formatter             *text.HelpFormatter
+	expectedFormattedHelp string
+	expectAnError         bool
+}
+
+func (testCase *HelpFormatterTestCase) RunTest() error {
+	formattedHelp, err := testCase.formatter.Format()
+
+	if testCase.expectAnError {
+		if err == nil {
+			return fmt.Errorf("[%s] expected error, got none", testCase.testName)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("[%s] expected no error, got = (%s)", testCase.testName, err)
+	}
+
+	if formattedHelp != testCase.expectedFormattedHelp {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedFormattedHelp, formattedHelp)
+	}
+
+	return nil
+}
+
+func helpFormatterWithFlags() *text.HelpFormatter {
+	return text.NewHelpFormatter().
+		UsingRowWidth(60).
+		AppendEntry("-o, --output=FILE", "Write the output to FILE instead of standard output, creating it if needed").
+		AppendEntry("-v, --verbose", "Produce verbose output").
+		AppendEntry("    --a-very-long-option-name=VALUE", "Definitions for terms that are too wide start on the next row").
+		AppendEntry("-h, --help", "")
+}
+
+func TestHelpFormatter(t *testing.T) {
+	testCases := []*HelpFormatterTestCase{
+		{
+			testName:  "computed definition column",
+			formatter: helpFormatterWithFlags(),
+			expectedFormattedHelp: "" +
+				"  -o, --output=FILE  Write the output to FILE instead of\n" +
+				"                     standard output, creating it if needed\n" +
+				"  -v, --verbose      Produce verbose output\n" +
+				"      --a-very-long-option-name=VALUE\n" +
+				"                     Definitions for terms that are too wide\n" +
+				"                     start on the next row\n" +
+				"  -h, --help",
+		},
+		{
+			testName:  "fixed definition column",
+			formatter: helpFormatterWithFlags().UsingDefinitionColumn(29),
+			expectedFormattedHelp: "" +
+				"  -o, --output=FILE          Write the output to FILE\n" +
+				"                             instead of standard output,\n" +
+				"                             creating it if needed\n" +
+				"  -v, --verbose              Produce verbose output\n" +
+				"      --a-very-long-option-name=VALUE\n" +
+				"                             Definitions for terms that are\n" +
+				"                             too wide start on the next row\n" +
+				"  -h, --help",
+		},
+		{
+			testName: "term wider than computed column",
+			formatter: text.NewHelpFormatter().
+				UsingRowWidth(40).
+				UsingTermIndentString(" ").
+				UsingMaximumDefinitionColumn(10).
+				UsingMinimumGapAfterTerm(1).
+				AppendEntry("list", "List ∀∁∂∃ entries").
+				AppendEntry("remove-all", "Remove every entry in the collection"),
+			expectedFormattedHelp: "" +
+				" list List ∀∁∂∃ entries\n" +
+				" remove-all\n" +
+				"      Remove every entry in the\n" +
+				"      collection",
+		},
+		{
+			testName:              "no entries",
+			formatter:             text.NewHelpFormatter(),
+			expectedFormattedHelp: "",
+		},
+		{
+			testName:      "definition column beyond row width",
+			formatter:     helpFormatterWithFlags().UsingDefinitionColumn(60),
+			expectAnError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package text
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ChangeIndentDetectionFromInputTo enables or disables indent detection. When it is enabled, each wrap operation
+// inspects the first two non-blank lines of the input. The leading whitespace on the second line becomes the indent
+// for rows after the first (the "hanging indent"). If the first line has a word that starts at the same column
+// where the second line's text starts, everything on the first line before that column (for example,
+// "  -o, --output  ") becomes the indent for the first row. Otherwise, the leading whitespace on the first line
+// becomes the indent for the first row. Leading and trailing whitespace is removed from each line after the first
+// before the text is wrapped, so text that was already wrapped with a hanging indent keeps its alignment when it
+// is re-wrapped to a different row width. The detected indents are used only for that wrap operation, in place of
+// the indents set by ChangeIndentStringForFirstRowTo() and ChangeIndentStringForRowsAfterTheFirstTo(). If a detected
+// indent is not shorter than the row width, the configured indents are used instead. Detection is disabled by default.
+func (wrapper *Wrapper) ChangeIndentDetectionFromInputTo(detectionIsEnabled bool) *Wrapper {
+	wrapper.detectIndentsFromInput = detectionIsEnabled
+	return wrapper
+}
+
+// UsingIndentsDetectedFromInput is the same as ChangeIndentDetectionFromInputTo(true), but provides a more readable
+// name if this is chained with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingRowWidth(60).UsingIndentsDetectedFromInput()
+func (wrapper *Wrapper) UsingIndentsDetectedFromInput() *Wrapper {
+	return wrapper.ChangeIndentDetectionFromInputTo(true)
+}
+
+type indentsDetectedFromInput struct {
+	firstRowIndent                   string
+	rowsAfterTheFirstIndent          string
+	firstLineTextAfterFirstRowIndent string
+}
+
+func detectIndentsFromFirstTwoLines(advancer runeAdvancer, firstLine string, secondLine string) *indentsDetectedFromInput {
+	firstLineRunes := []rune(firstLine)
+	leadingWhitespaceOnFirstLine := leadingWhitespaceIn(firstLine)
+
+	if strings.TrimSpace(secondLine) == "" {
+		return &indentsDetectedFromInput{
+			firstRowIndent:                   leadingWhitespaceOnFirstLine,
+			rowsAfterTheFirstIndent:          leadingWhitespaceOnFirstLine,
+			firstLineTextAfterFirstRowIndent: firstLine[len(leadingWhitespaceOnFirstLine):],
+		}
+	}
+
+	hangingIndent := leadingWhitespaceIn(secondLine)
+	hangingIndentColumn := widthOfStringUsing(advancer, hangingIndent)
+	indexOfRuneAtHangingIndentColumn := indexOfFirstRuneAfterColumns(advancer, firstLineRunes, hangingIndentColumn)
+
+	if hangingIndentColumn > 0 && len(firstLineRunes) > indexOfRuneAtHangingIndentColumn &&
+		widthOfRunesUsing(advancer, firstLineRunes[:indexOfRuneAtHangingIndentColumn]) == hangingIndentColumn &&
+		unicode.IsSpace(firstLineRunes[indexOfRuneAtHangingIndentColumn-1]) && !unicode.IsSpace(firstLineRunes[indexOfRuneAtHangingIndentColumn]) {
+		return &indentsDetectedFromInput{
+			firstRowIndent:                   string(firstLineRunes[:indexOfRuneAtHangingIndentColumn]),
+			rowsAfterTheFirstIndent:          hangingIndent,
+			firstLineTextAfterFirstRowIndent: string(firstLineRunes[indexOfRuneAtHangingIndentColumn:]),
+		}
+	}
+
+	return &indentsDetectedFromInput{
+		firstRowIndent:                   leadingWhitespaceOnFirstLine,
+		rowsAfterTheFirstIndent:          hangingIndent,
+		firstLineTextAfterFirstRowIndent: firstLine[len(leadingWhitespaceOnFirstLine):],
+	}
+}
+
+func leadingWhitespaceIn(line string) string {
+	return line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+}
+
+func lineWithLineBreakRemoved(line string) string {
+	return strings.TrimRight(line, "\r\n")
+}
+
+func lineWithSurroundingWhitespaceRemoved(line string) string {
+	if strings.HasSuffix(line, "\n") {
+		return strings.TrimSpace(line) + "\n"
+	}
+
+	return strings.TrimSpace(line)
+}
+
+// wrapUsingIndentsDetectedFrom wraps the text from reader using the indents detected from its first two lines. If
+// reading either of those lines fails, the text read before the error is wrapped, and the error is returned in a
+// WrapError, as it is for an error later in the text.
+func (wrapper *Wrapper) wrapUsingIndentsDetectedFrom(reader io.Reader) (wrappedText string, contentWasDropped bool, err error) {
+	lineReader := bufio.NewReader(reader)
+
+	var firstLine string
+	for {
+		firstLine, err = lineReader.ReadString('\n')
+		if strings.TrimSpace(firstLine) != "" || err != nil {
+			break
+		}
+	}
+
+	if strings.TrimSpace(firstLine) == "" && err == io.EOF {
+		return "", false, nil
+	}
+
+	secondLine := ""
+	if err == nil {
+		secondLine, err = lineReader.ReadString('\n')
+	}
+
+	detectedIndents := detectIndentsFromFirstTwoLines(wrapper.columnAdvancer(), lineWithLineBreakRemoved(firstLine), lineWithLineBreakRemoved(secondLine))
+
+	wrapperUsingDetectedIndents := *wrapper
+	firstLineTextToWrap := firstLine
+
+	if wrapper.widthOfRunes([]rune(detectedIndents.firstRowIndent)) < int(wrapper.rowWidthInWidthUnits()) && wrapper.widthOfRunes([]rune(detectedIndents.rowsAfterTheFirstIndent)) < int(wrapper.rowWidthInWidthUnits()) {
+		wrapperUsingDetectedIndents.initialLineIndentString = []rune(detectedIndents.firstRowIndent)
+		wrapperUsingDetectedIndents.subsequentLinesIndentString = []rune(detectedIndents.rowsAfterTheFirstIndent)
+		firstLineTextToWrap = detectedIndents.firstLineTextAfterFirstRowIndent + firstLine[len(lineWithLineBreakRemoved(firstLine)):]
+	}
+
+	textBeforeRemainingLines := lineWithSurroundingWhitespaceRemoved(firstLineTextToWrap) + lineWithSurroundingWhitespaceRemoved(secondLine)
+
+	// if reading the first two lines stopped, with io.EOF or an error, the rest of the input ends with the same error
+	reassembledReader := io.MultiReader(strings.NewReader(textBeforeRemainingLines), &lineWhitespaceTrimmingReader{lineSource: lineReader, errorFromLastLineSource: err})
+
+	return wrapperUsingDetectedIndents.wrapFromReaderAndTruncate(reassembledReader)
+}
+
+// lineWhitespaceTrimmingReader is an io.Reader that reads lines from lineSource, removing leading and trailing
+// whitespace (other than the line break) from each line.
+type lineWhitespaceTrimmingReader struct {
+	lineSource              *bufio.Reader
+	bytesOfCurrentLine      []byte
+	errorFromLastLineSource error
+}
+
+func (reader *lineWhitespaceTrimmingReader) Read(p []byte) (int, error) {
+	for len(reader.bytesOfCurrentLine) == 0 {
+		if reader.errorFromLastLineSource != nil {
+			return 0, reader.errorFromLastLineSource
+		}
+
+		line, err := reader.lineSource.ReadString('\n')
+		reader.bytesOfCurrentLine = []byte(lineWithSurroundingWhitespaceRemoved(line))
+		reader.errorFromLastLineSource = err
+	}
+
+	bytesCopied := copy(p, reader.bytesOfCurrentLine)
+	reader.bytesOfCurrentLine = reader.bytesOfCurrentLine[bytesCopied:]
+
+	return bytesCopied, nil
+}
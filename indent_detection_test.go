@@ -0,0 +1,156 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/blorticus-go/text"
+)
+
+type IndentDetectionTestCase struct {
+	testName              string
+	rowLength             uint
+	unwrappedString       string
+	expectedWrappedString string
+}
+
+func (testCase *IndentDetectionTestCase) RunTest() error {
+	wrapper := text.NewWrapper().
+		UsingRowWidth(testCase.rowLength).
+		UsingIndentStringForFirstRow(">").
+		UsingIndentStringForRowsAfterTheFirst(">>").
+		UsingIndentsDetectedFromInput()
+
+	wrappedString, err := wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] on WrapStringText(), got error = (%s)", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] on WrapStringText(), expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	wrappedString, err = wrapper.WrapUTF8TextFromAReader(strings.NewReader(testCase.unwrappedString))
+	if err != nil {
+		return fmt.Errorf("[%s] on WrapUTF8TextFromAReader(), got error = (%s)", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] on WrapUTF8TextFromAReader(), expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestIndentDetection(t *testing.T) {
+	testCases := []*IndentDetectionTestCase{
+		{
+			testName:  "option and description",
+			rowLength: 40,
+			unwrappedString: "" +
+				"  -o, --output  Write the output to the named file instead of\n" +
+				"                standard output, creating it if needed.\n",
+			expectedWrappedString: "" +
+				"  -o, --output  Write the output to the\n" +
+				"                named file instead of\n" +
+				"                standard output,\n" +
+				"                creating it if needed.",
+		},
+		{
+			testName:  "first line indent only",
+			rowLength: 40,
+			unwrappedString: "" +
+				"    This paragraph has a first-line indent and then\n" +
+				"the text continues at the margin and keeps going for a while.",
+			expectedWrappedString: "" +
+				"    This paragraph has a first-line\n" +
+				"indent and then the text continues at\n" +
+				"the margin and keeps going for a while.",
+		},
+		{
+			testName:        "single indented line",
+			rowLength:       40,
+			unwrappedString: "   single line that is indented and long enough to wrap around",
+			expectedWrappedString: "" +
+				"   single line that is indented and long\n" +
+				"   enough to wrap around",
+		},
+		{
+			testName:              "leading blank lines",
+			rowLength:             40,
+			unwrappedString:       "\n\r\n  -v  Be verbose.\n",
+			expectedWrappedString: "  -v  Be verbose.",
+		},
+		{
+			testName:              "empty input",
+			rowLength:             40,
+			unwrappedString:       "",
+			expectedWrappedString: "",
+		},
+		{
+			testName:  "detected indent too wide for row",
+			rowLength: 12,
+			unwrappedString: "" +
+				"  --output      Write the output\n" +
+				"                to a file.",
+			expectedWrappedString: "" +
+				">--output\n" +
+				">>Write the\n" +
+				">>output to\n" +
+				">>a file.",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestIndentDetectionWithAReadError(t *testing.T) {
+	errorFromReader := errors.New("read failed")
+	wrapper := text.NewWrapper().UsingRowWidth(30).UsingIndentsDetectedFromInput()
+
+	testCases := []struct {
+		testName              string
+		textBeforeTheError    string
+		expectedWrappedString string
+		expectedWrapError     *text.WrapError
+	}{
+		{
+			testName:              "error before the first line",
+			textBeforeTheError:    "\n  \n",
+			expectedWrappedString: "",
+			expectedWrapError:     &text.WrapError{Err: errorFromReader, ByteOffset: 0, RuneOffset: 0, Line: 1, Row: 1},
+		},
+		{
+			testName:              "error in the first line",
+			textBeforeTheError:    "  -o  Write the output to the named",
+			expectedWrappedString: "  -o  Write the output to the",
+			expectedWrapError:     &text.WrapError{Err: errorFromReader, ByteOffset: 27, RuneOffset: 27, Line: 1, Row: 1},
+		},
+		{
+			testName:              "error in the second line",
+			textBeforeTheError:    "  -o  Write the output to the named\n      file instead of st",
+			expectedWrappedString: "  -o  Write the output to the\n      named file instead of",
+			expectedWrapError:     &text.WrapError{Err: errorFromReader, ByteOffset: 45, RuneOffset: 45, Line: 2, Row: 2},
+		},
+	}
+
+	for _, testCase := range testCases {
+		wrappedString, err := wrapper.WrapUTF8TextFromAReader(io.MultiReader(strings.NewReader(testCase.textBeforeTheError), iotest.ErrReader(errorFromReader)))
+
+		if err := errorUnlessTheExpectedWrapError(testCase.expectedWrapError, err); err != nil {
+			t.Errorf("[%s] %s", testCase.testName, err)
+		}
+
+		if wrappedString != testCase.expectedWrappedString {
+			t.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package text
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// InvalidUTF8Policy is how a Wrapper handles bytes in its input that are not valid UTF-8, including a multi-byte
+// sequence that is cut short by the end of the input.
+type InvalidUTF8Policy int
+
+const (
+	// FailOnInvalidUTF8 stops wrapping at the first invalid byte, and returns an *InvalidUTF8Error, along with the
+	// text wrapped before the word that contains the invalid byte. This is the default.
+	FailOnInvalidUTF8 InvalidUTF8Policy = iota
+
+	// ReplaceInvalidUTF8 replaces each invalid byte with U+FFFD (the Unicode replacement character).
+	ReplaceInvalidUTF8
+
+	// PassInvalidUTF8Through copies each invalid byte, unchanged, to the wrapped text, and counts it as one column.
+	// An invalid byte is never separated from the non-whitespace characters around it. If a row that contains an
+	// invalid byte is changed after wrapping (by truncation or bidi reordering), the invalid bytes in that row are
+	// replaced with U+FFFD.
+	PassInvalidUTF8Through
+)
+
+// InvalidUTF8Error is returned when a Wrapper with the FailOnInvalidUTF8 policy reads a byte that is not valid
+// UTF-8.
+type InvalidUTF8Error struct {
+	// ByteOffset is the offset of the invalid byte from the start of the UTF-8 text being wrapped (after any
+	// decoding from another encoding or normalization, and, if indents are detected from the input, after the
+	// whitespace around each line is removed).
+	ByteOffset int64
+
+	// Byte is the invalid byte.
+	Byte byte
+}
+
+func (err *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("invalid UTF-8 byte 0x%02x at byte offset %d", err.Byte, err.ByteOffset)
+}
+
+// ChangeInvalidUTF8PolicyTo sets how bytes that are not valid UTF-8 are handled. The default is FailOnInvalidUTF8.
+func (wrapper *Wrapper) ChangeInvalidUTF8PolicyTo(policy InvalidUTF8Policy) *Wrapper {
+	wrapper.invalidUTF8Policy = policy
+	return wrapper
+}
+
+// UsingInvalidUTF8Policy is the same as ChangeInvalidUTF8PolicyTo(), but provides a more readable name if this is
+// chained with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingInvalidUTF8Policy(text.ReplaceInvalidUTF8).UsingRowWidth(40)
+func (wrapper *Wrapper) UsingInvalidUTF8Policy(policy InvalidUTF8Policy) *Wrapper {
+	return wrapper.ChangeInvalidUTF8PolicyTo(policy)
+}
+
+// An invalid byte that is passed through is represented, while it is being wrapped, by a rune in the range
+// U+DC80 to U+DCFF (the low surrogates), which cannot be decoded from valid UTF-8.
+const rawByteRuneBase = 0xdc00
+
+func rawByteRune(b byte) rune {
+	return rawByteRuneBase + rune(b)
+}
+
+func isRawByteRune(r rune) bool {
+	return r >= rawByteRuneBase+0x80 && r <= rawByteRuneBase+0xff
+}
+
+// writeRunesInto writes runes into bufferOfWrappedText as UTF-8, except that runes that represent invalid bytes are
+// written as those bytes.
+func writeRunesInto(bufferOfWrappedText *bytes.Buffer, runes []rune) error {
+	for _, r := range runes {
+		if isRawByteRune(r) {
+			if err := bufferOfWrappedText.WriteByte(byte(r - rawByteRuneBase)); err != nil {
+				return err
+			}
+		} else if _, err := bufferOfWrappedText.WriteRune(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/blorticus-go/text"
+)
+
+type InvalidUTF8TestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	reader                io.Reader
+	expectedWrappedString string
+	expectedByteOffset    int64
+	expectedInvalidByte   byte
+	expectAnError         bool
+}
+
+func (testCase *InvalidUTF8TestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapUTF8TextFromAReader(testCase.reader)
+
+	if testCase.expectAnError {
+		var invalidUTF8Error *text.InvalidUTF8Error
+		if !errors.As(err, &invalidUTF8Error) {
+			return fmt.Errorf("[%s] expected an InvalidUTF8Error, got = (%v)", testCase.testName, err)
+		}
+
+		if invalidUTF8Error.ByteOffset != testCase.expectedByteOffset || invalidUTF8Error.Byte != testCase.expectedInvalidByte {
+			return fmt.Errorf("[%s] expected byte 0x%02x at offset %d, got byte 0x%02x at offset %d", testCase.testName, testCase.expectedInvalidByte, testCase.expectedByteOffset, invalidUTF8Error.Byte, invalidUTF8Error.ByteOffset)
+		}
+	} else if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestWrapInvalidUTF8(t *testing.T) {
+	failing := text.NewWrapper().UsingRowWidth(7)
+	replacing := text.NewWrapper().UsingRowWidth(7).UsingInvalidUTF8Policy(text.ReplaceInvalidUTF8)
+	passingThrough := text.NewWrapper().UsingRowWidth(7).UsingInvalidUTF8Policy(text.PassInvalidUTF8Through)
+
+	testCases := []*InvalidUTF8TestCase{
+		{
+			testName:              "fail reports the byte offset",
+			wrapper:               failing,
+			reader:                strings.NewReader("abc de\xfff ghi"),
+			expectedWrappedString: "abc",
+			expectedByteOffset:    6,
+			expectedInvalidByte:   0xff,
+			expectAnError:         true,
+		},
+		{
+			testName:              "fail on a sequence truncated by the end of the input",
+			wrapper:               failing,
+			reader:                strings.NewReader("abc 日\xe6\x97"),
+			expectedWrappedString: "abc",
+			expectedByteOffset:    7,
+			expectedInvalidByte:   0xe6,
+			expectAnError:         true,
+		},
+		{
+			testName:              "U+FFFD in the input is valid",
+			wrapper:               failing,
+			reader:                strings.NewReader("abc �"),
+			expectedWrappedString: "abc �",
+		},
+		{
+			testName:              "replace",
+			wrapper:               replacing,
+			reader:                strings.NewReader("abc de\xfff ghi"),
+			expectedWrappedString: "abc\nde�f\nghi",
+		},
+		{
+			testName:              "replace each byte of a truncated sequence",
+			wrapper:               replacing,
+			reader:                strings.NewReader("abc 日\xe6\x97"),
+			expectedWrappedString: "abc 日��",
+		},
+		{
+			testName:              "pass through",
+			wrapper:               passingThrough,
+			reader:                strings.NewReader("abc de\xfff ghi"),
+			expectedWrappedString: "abc\nde\xfff\nghi",
+		},
+		{
+			testName:              "pass through counts each byte as one column",
+			wrapper:               text.NewWrapper().UsingRowWidth(7).UsingInvalidUTF8Policy(text.PassInvalidUTF8Through).UsingMeasurer(text.EastAsianWideMeasurer),
+			reader:                strings.NewReader("a \xe6\x97\xe6\x97\xe6\x97 b"),
+			expectedWrappedString: "a\n\xe6\x97\xe6\x97\xe6\x97\nb",
+		},
+		{
+			testName:              "pass through a truncated sequence at the end of the input",
+			wrapper:               passingThrough,
+			reader:                strings.NewReader("abc 日\xe6\x97"),
+			expectedWrappedString: "abc 日\xe6\x97",
+		},
+		{
+			testName:              "sequences split across reads",
+			wrapper:               failing,
+			reader:                iotest.OneByteReader(strings.NewReader("日本語 テキスト")),
+			expectedWrappedString: "日本語\nテキスト",
+		},
+		{
+			testName:              "truncated sequence split across reads",
+			wrapper:               replacing,
+			reader:                iotest.OneByteReader(strings.NewReader("abc 日\xe6\x97")),
+			expectedWrappedString: "abc 日��",
+		},
+		{
+			testName:              "truncated sequence returned with io.EOF",
+			wrapper:               passingThrough,
+			reader:                iotest.DataErrReader(iotest.HalfReader(strings.NewReader("abc 日\xe6\x97"))),
+			expectedWrappedString: "abc 日\xe6\x97",
+		},
+		{
+			testName:              "invalid byte after a sequence split across reads",
+			wrapper:               failing,
+			reader:                iotest.OneByteReader(strings.NewReader("日本\xe8x")),
+			expectedWrappedString: "",
+			expectedByteOffset:    6,
+			expectedInvalidByte:   0xe8,
+			expectAnError:         true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestWrapInvalidUTF8InStrings(t *testing.T) {
+	if _, err := text.NewWrapper().WrapStringText("abc \xff"); err == nil {
+		t.Errorf("expected an error for an invalid string, got none")
+	}
+
+	wrappedString, err := text.NewWrapper().UsingInvalidUTF8Policy(text.ReplaceInvalidUTF8).WrapStringText("abc \xff")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if wrappedString != "abc �" {
+		t.Errorf("expected = (%q), got = (%q)", "abc �", wrappedString)
+	}
+}
+
+func TestWrapSequenceThatStraddlesTheReadBuffer(t *testing.T) {
+	for lengthOfPrefix := 4090; lengthOfPrefix < 4100; lengthOfPrefix++ {
+		unwrappedString := strings.Repeat("a", lengthOfPrefix%2) + strings.Repeat(" a", lengthOfPrefix/2) + "日本語"
+
+		wrappedString, err := text.NewWrapper().UsingRowWidth(10).WrapUTF8TextFromAReader(strings.NewReader(unwrappedString))
+		if err != nil {
+			t.Fatalf("unexpected error for prefix of %d bytes: %s", lengthOfPrefix, err)
+		}
+
+		if rejoinedString := strings.Join(strings.Split(wrappedString, "\n"), " "); rejoinedString != strings.TrimPrefix(unwrappedString, " ") {
+			t.Errorf("for prefix of %d bytes, expected rows to rejoin into the unwrapped string, got = (%q)", lengthOfPrefix, wrappedString[len(wrappedString)-20:])
+		}
+	}
+}
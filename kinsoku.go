@@ -0,0 +1,153 @@
+package text
+
+import "io"
+
+// KinsokuAdjustment is how a Wrapper moves a row break that would start a row with a character that is prohibited
+// at the start of a row, or end a row with a character that is prohibited at the end of a row (kinsoku shori, the
+// Japanese line breaking rules). Kinsoku applies where a row is broken between two non-whitespace characters,
+// which is how text written without spaces, such as Japanese, is wrapped.
+type KinsokuAdjustment int
+
+const (
+	// KinsokuDisabled breaks rows without regard to prohibited characters. This is the default.
+	KinsokuDisabled KinsokuAdjustment = iota
+
+	// KinsokuPushOut moves characters from the end of the row to the start of the next row, until the row does not
+	// end with a character prohibited at the end of a row and the next row does not start with a character
+	// prohibited at the start of a row.
+	KinsokuPushOut
+
+	// KinsokuPushIn keeps characters that are prohibited at the start of a row on the end of the previous row, even
+	// if this makes the row wider than the row width (the characters hang past the end of the row). A row that would
+	// end with a character prohibited at the end of a row is adjusted as for KinsokuPushOut.
+	KinsokuPushIn
+)
+
+// DefaultKinsokuProhibitedAtStartOfRow are the characters that, by default, do not start a row when kinsoku is
+// enabled: closing brackets and quotation marks, small kana, the prolonged sound mark, iteration marks, and
+// punctuation such as 。 and 、.
+const DefaultKinsokuProhibitedAtStartOfRow = ")]}）］｝〕〉》」』】〙〗〟’”｠»" +
+	"ぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿｧｨｩｪｫｬｭｮｯ" +
+	"ーｰ々〻ゝゞヽヾ゛゜" +
+	"、。，．,.・：；:;？！?!‼⁇⁈⁉‐゠–〜…‥"
+
+// DefaultKinsokuProhibitedAtEndOfRow are the characters that, by default, do not end a row when kinsoku is
+// enabled: opening brackets and quotation marks.
+const DefaultKinsokuProhibitedAtEndOfRow = "([{（［｛〔〈《「『【〘〖〝‘“｟«"
+
+// defaultKinsokuProhibitedAtStartOfRow and defaultKinsokuProhibitedAtEndOfRow are shared by every Wrapper that uses
+// the default prohibited characters. They are never changed: ChangeKinsokuProhibitedCharactersTo() makes new sets.
+var (
+	defaultKinsokuProhibitedAtStartOfRow = setOfRunesIn(DefaultKinsokuProhibitedAtStartOfRow)
+	defaultKinsokuProhibitedAtEndOfRow   = setOfRunesIn(DefaultKinsokuProhibitedAtEndOfRow)
+)
+
+// ChangeKinsokuAdjustmentTo enables or disables kinsoku, and sets how a row break is moved when it would break
+// between prohibited characters. The default is KinsokuDisabled. The prohibited characters are set by
+// ChangeKinsokuProhibitedCharactersTo().
+func (wrapper *Wrapper) ChangeKinsokuAdjustmentTo(adjustment KinsokuAdjustment) *Wrapper {
+	wrapper.kinsokuAdjustment = adjustment
+	return wrapper
+}
+
+// UsingKinsokuAdjustment is the same as ChangeKinsokuAdjustmentTo(), but provides a more readable name if this is
+// chained with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingKinsokuAdjustment(text.KinsokuPushIn).UsingRowWidth(40)
+func (wrapper *Wrapper) UsingKinsokuAdjustment(adjustment KinsokuAdjustment) *Wrapper {
+	return wrapper.ChangeKinsokuAdjustmentTo(adjustment)
+}
+
+// ChangeKinsokuProhibitedCharactersTo sets the characters that may not start a row and the characters that may not
+// end a row, when kinsoku is enabled. The defaults are DefaultKinsokuProhibitedAtStartOfRow and
+// DefaultKinsokuProhibitedAtEndOfRow.
+func (wrapper *Wrapper) ChangeKinsokuProhibitedCharactersTo(prohibitedAtStartOfRow string, prohibitedAtEndOfRow string) *Wrapper {
+	wrapper.kinsokuProhibitedAtStartOfRow = setOfRunesIn(prohibitedAtStartOfRow)
+	wrapper.kinsokuProhibitedAtEndOfRow = setOfRunesIn(prohibitedAtEndOfRow)
+	return wrapper
+}
+
+// UsingKinsokuProhibitedCharacters is the same as ChangeKinsokuProhibitedCharactersTo(), but provides a more
+// readable name if this is chained with the constructor.
+func (wrapper *Wrapper) UsingKinsokuProhibitedCharacters(prohibitedAtStartOfRow string, prohibitedAtEndOfRow string) *Wrapper {
+	return wrapper.ChangeKinsokuProhibitedCharactersTo(prohibitedAtStartOfRow, prohibitedAtEndOfRow)
+}
+
+func setOfRunesIn(s string) map[rune]bool {
+	setOfRunes := make(map[rune]bool)
+	for _, r := range s {
+		setOfRunes[r] = true
+	}
+
+	return setOfRunes
+}
+
+// afterApplyingKinsokuTo is called when the runes in rowEnding end a row because the next rune in the nibbler did
+// not fit. It moves the end of the row according to the kinsoku adjustment, reading runes from the nibbler onto the
+// end of rowEnding (push in) or unreading runes from the end of rowEnding (push out), and returns the adjusted
+// runes. If the row cannot be adjusted without removing all of rowEnding, rowEnding is returned unchanged.
+func (wrapper *Wrapper) afterApplyingKinsokuTo(nibbler *breakOpportunityNibbler, rowEnding []rune) ([]rune, error) {
+	if wrapper.kinsokuAdjustment == KinsokuDisabled || len(rowEnding) == 0 {
+		return rowEnding, nil
+	}
+
+	if wrapper.kinsokuAdjustment == KinsokuPushIn && !wrapper.kinsokuProhibitedAtEndOfRow[lastRuneOf(rowEnding)] {
+		for {
+			nextRune, err := nibbler.PeekAtNextCharacter()
+			if err != nil {
+				return rowEnding, ignoringEOF(err)
+			}
+
+			if !wrapper.kinsokuProhibitedAtStartOfRow[nextRune] && !runeExtendsPreviousCharacter(nextRune) {
+				return rowEnding, nil
+			}
+
+			if _, err := nibbler.ReadCharacter(); err != nil {
+				return rowEnding, err
+			}
+
+			rowEnding = append(rowEnding, nextRune)
+		}
+	}
+
+	numberOfRunesPushedOut := 0
+	for wrapper.rowMayNotEndBetween(nibbler, rowEnding[len(rowEnding)-1-numberOfRunesPushedOut], rowEnding[len(rowEnding)-numberOfRunesPushedOut:]) {
+		if numberOfRunesPushedOut == len(rowEnding)-1 {
+			return rowEnding, nil
+		}
+
+		numberOfRunesPushedOut++
+	}
+
+	for runeIndex := 0; runeIndex < numberOfRunesPushedOut; runeIndex++ {
+		if err := nibbler.UnreadCharacter(); err != nil {
+			return rowEnding, err
+		}
+	}
+
+	return rowEnding[:len(rowEnding)-numberOfRunesPushedOut], nil
+}
+
+// rowMayNotEndBetween returns true if a row may not end with lastRuneInRow when the next row starts with
+// runesPushedOut or, if there are none, with the next rune in the nibbler.
+func (wrapper *Wrapper) rowMayNotEndBetween(nibbler *breakOpportunityNibbler, lastRuneInRow rune, runesPushedOut []rune) bool {
+	if wrapper.kinsokuProhibitedAtEndOfRow[lastRuneInRow] {
+		return true
+	}
+
+	firstRuneInNextRow := noPreviousRune
+	if len(runesPushedOut) > 0 {
+		firstRuneInNextRow = runesPushedOut[0]
+	} else if nextRune, err := nibbler.PeekAtNextCharacter(); err == nil {
+		firstRuneInNextRow = nextRune
+	}
+
+	return wrapper.kinsokuProhibitedAtStartOfRow[firstRuneInNextRow] || runeExtendsPreviousCharacter(firstRuneInNextRow)
+}
+
+func ignoringEOF(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+
+	return err
+}
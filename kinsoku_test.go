@@ -0,0 +1,122 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type KinsokuTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	unwrappedString       string
+	expectedWrappedString string
+}
+
+func (testCase *KinsokuTestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestWrapUsingKinsoku(t *testing.T) {
+	withoutKinsoku := text.NewWrapper().UsingRowWidth(5)
+	pushOut := text.NewWrapper().UsingRowWidth(5).UsingKinsokuAdjustment(text.KinsokuPushOut)
+	pushIn := text.NewWrapper().UsingRowWidth(5).UsingKinsokuAdjustment(text.KinsokuPushIn)
+
+	testCases := []*KinsokuTestCase{
+		{
+			testName:              "without kinsoku a row may start with 。",
+			wrapper:               withoutKinsoku,
+			unwrappedString:       "あいうえお。かきくけこ",
+			expectedWrappedString: "あいうえお\n。かきくけ\nこ",
+		},
+		{
+			testName:              "push out before 。",
+			wrapper:               pushOut,
+			unwrappedString:       "あいうえお。かきくけこ",
+			expectedWrappedString: "あいうえ\nお。かきく\nけこ",
+		},
+		{
+			testName:              "push in 。",
+			wrapper:               pushIn,
+			unwrappedString:       "あいうえお。かきくけこ",
+			expectedWrappedString: "あいうえお。\nかきくけこ",
+		},
+		{
+			testName:              "push out before small kana",
+			wrapper:               pushOut,
+			unwrappedString:       "あいうえおっかきくけ",
+			expectedWrappedString: "あいうえ\nおっかきく\nけ",
+		},
+		{
+			testName:              "push in closing bracket and 。 together",
+			wrapper:               pushIn,
+			unwrappedString:       "あいうえお」。かきく",
+			expectedWrappedString: "あいうえお」。\nかきく",
+		},
+		{
+			testName:              "push out closing bracket and 。 together",
+			wrapper:               pushOut,
+			unwrappedString:       "あいうえお」。かきく",
+			expectedWrappedString: "あいうえ\nお」。かき\nく",
+		},
+		{
+			testName:              "push out opening bracket at end of row",
+			wrapper:               pushOut,
+			unwrappedString:       "あいうえ「かきくけこ",
+			expectedWrappedString: "あいうえ\n「かきくけ\nこ",
+		},
+		{
+			testName:              "push in cannot hang an opening bracket, so it is pushed out",
+			wrapper:               pushIn,
+			unwrappedString:       "あいうえ「かきくけこ",
+			expectedWrappedString: "あいうえ\n「かきくけ\nこ",
+		},
+		{
+			testName:              "row that cannot be adjusted is broken as without kinsoku",
+			wrapper:               pushOut,
+			unwrappedString:       "「「「「「「",
+			expectedWrappedString: "「「「「「\n「",
+		},
+		{
+			testName:              "double width characters",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingMeasurer(text.EastAsianWideMeasurer).UsingKinsokuAdjustment(text.KinsokuPushOut),
+			unwrappedString:       "これはテス、日本語です。",
+			expectedWrappedString: "これはテ\nス、日本語\nです。",
+		},
+		{
+			testName: "custom prohibited characters",
+			wrapper: text.NewWrapper().UsingRowWidth(5).UsingKinsokuAdjustment(text.KinsokuPushOut).
+				UsingKinsokuProhibitedCharacters("か", ""),
+			unwrappedString:       "あいうえお。かきくけこ",
+			expectedWrappedString: "あいうえお\n。かきくけ\nこ",
+		},
+		{
+			testName:              "default prohibited characters after another Wrapper changed its own",
+			wrapper:               text.NewWrapper().UsingRowWidth(5).UsingKinsokuAdjustment(text.KinsokuPushOut),
+			unwrappedString:       "あいうえお。かきくけこ",
+			expectedWrappedString: "あいうえ\nお。かきく\nけこ",
+		},
+		{
+			testName:              "break at whitespace is unaffected",
+			wrapper:               pushOut,
+			unwrappedString:       "あいう 」えお",
+			expectedWrappedString: "あいう\n」えお",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrLayoutIsNotAvailable is returned by LayOutStringText() if the Wrapper changes the wrapped text in a way that
+// a Layout cannot map.
+var ErrLayoutIsNotAvailable = errors.New("layout is not available")
+
+// Layout is text wrapped by a Wrapper, with a map between the positions of the characters in the text and their
+// positions in the wrapped rows. It is meant for editors and pagers, which move a cursor between the text and the
+// wrapped view of it.
+//
+// The map follows the changes that the Wrapper makes to the text when it wraps it. Each whitespace character that
+// is written to the wrapped text is written as one space, which has its own position in the rows. Whitespace that
+// is not written (at the start and the end of the text, and where a row is broken) has no position of its own, so
+// it is at the position after the character that is written before it. Indents and line breaks are not in the
+// text, so no position in the text is in them.
+type Layout struct {
+	text                  string
+	rows                  []string
+	characters            []laidOutCharacter
+	indexOfFirstCharacter []int
+	columnAfterTheIndent  []int
+}
+
+// laidOutCharacter is a character of the text of a Layout that is written to the wrapped text. Its bytes are
+// text[byteOffset:byteOffset+byteLength], and it is width columns wide, starting at column of row.
+type laidOutCharacter struct {
+	byteOffset int
+	byteLength int
+	row        int
+	column     int
+	width      int
+}
+
+// VisualPosition is a position in the rows of a Layout.
+type VisualPosition struct {
+	// Row is the index of the row, starting at 0.
+	Row int
+
+	// Column is the width of the part of the row before the position, including the indent. It is measured as the
+	// Wrapper measures rows. If the Wrapper has FontMetrics, Column is in 64ths of a point (the unit of the
+	// fixed.Int26_6 values of golang.org/x/image/font), so Column/64 is the width in points. Otherwise, Column is in
+	// columns, as counted by the Measurer.
+	Column int
+}
+
+// LayOutStringText wraps unwrappedString, as WrapStringText() does, and returns the Layout of the wrapped text. If
+// wrapping fails, the error from WrapStringText() is returned. A Layout cannot be made if the Wrapper reorders
+// bidi text, truncates the wrapped text to a maximum number of rows, or detects indents from the input, because
+// those change where the characters of the text are in the rows. In those cases, an error that wraps
+// ErrLayoutIsNotAvailable is returned.
+//
+// Positions in the text of the Layout are byte offsets in Text(), which is the text that is wrapped: that is,
+// unwrappedString after any InputNormalization.
+func (wrapper *Wrapper) LayOutStringText(unwrappedString string) (*Layout, error) {
+	if wrapper.bidiMode != BidiDisabled {
+		return nil, fmt.Errorf("%w: rows are reordered for bidi text", ErrLayoutIsNotAvailable)
+	} else if wrapper.maximumRows > 0 {
+		return nil, fmt.Errorf("%w: wrapped text is truncated to %d rows", ErrLayoutIsNotAvailable, wrapper.maximumRows)
+	} else if wrapper.detectIndentsFromInput {
+		return nil, fmt.Errorf("%w: indents are detected from the input", ErrLayoutIsNotAvailable)
+	}
+
+	wrappedText, err := wrapper.WrapStringText(unwrappedString)
+	if err != nil {
+		return nil, err
+	}
+
+	textThatIsWrapped := unwrappedString
+	if wrapper.inputNormalization != NoNormalization {
+		normalizedText, err := io.ReadAll(wrapper.normalizingReaderFor(strings.NewReader(unwrappedString)))
+		if err != nil {
+			return nil, err
+		}
+
+		textThatIsWrapped = string(normalizedText)
+	}
+
+	return wrapper.layoutOf(textThatIsWrapped, wrappedText)
+}
+
+// layoutOf finds the character of textThatIsWrapped that each character of wrappedText was written from. Because
+// the Wrapper writes characters in order, writes every whitespace character in a run of whitespace or none of them,
+// and writes each whitespace character as a space, each character of a row is written from the first character
+// of the text after the previous one that is not whitespace that was dropped.
+func (wrapper *Wrapper) layoutOf(textThatIsWrapped string, wrappedText string) (*Layout, error) {
+	layout := &Layout{
+		text:                  textThatIsWrapped,
+		rows:                  strings.Split(wrappedText, wrapper.lineBreakSequence),
+		characters:            nil,
+		indexOfFirstCharacter: nil,
+		columnAfterTheIndent:  nil,
+	}
+
+	advancer := wrapper.advancer()
+	offsetInText := 0
+
+	for rowIndex, row := range layout.rows {
+		indent := wrapper.subsequentLinesIndentString
+		if rowIndex == 0 {
+			indent = wrapper.initialLineIndentString
+		}
+
+		column := widthOfRunesUsing(advancer, indent)
+		previousRune := lastRuneOf(indent)
+
+		layout.indexOfFirstCharacter = append(layout.indexOfFirstCharacter, len(layout.characters))
+		layout.columnAfterTheIndent = append(layout.columnAfterTheIndent, column)
+
+		offsetInRow := len(string(indent))
+		if row == "" {
+			// only the wrapped text of text that is empty or only whitespace has an empty row
+			offsetInRow = 0
+		}
+
+		for offsetInRow < len(row) {
+			writtenRune, writtenLength := utf8.DecodeRuneInString(row[offsetInRow:])
+			if writtenRune == utf8.RuneError && writtenLength == 1 {
+				writtenRune = rawByteRune(row[offsetInRow])
+			}
+
+			characterOffset, characterLength, characterWasFound := offsetOfCharacterWrittenAs(writtenRune, textThatIsWrapped, offsetInText)
+			if !characterWasFound {
+				return nil, fmt.Errorf("%w: wrapped text in row %d does not match the text at byte offset %d", ErrLayoutIsNotAvailable, rowIndex, offsetInText)
+			}
+
+			widthOfCharacter := advancer.advanceOf(previousRune, writtenRune)
+
+			layout.characters = append(layout.characters, laidOutCharacter{
+				byteOffset: characterOffset,
+				byteLength: characterLength,
+				row:        rowIndex,
+				column:     column,
+				width:      widthOfCharacter,
+			})
+
+			column += widthOfCharacter
+			previousRune = writtenRune
+			offsetInText = characterOffset + characterLength
+			offsetInRow += writtenLength
+		}
+	}
+
+	layout.indexOfFirstCharacter = append(layout.indexOfFirstCharacter, len(layout.characters))
+
+	return layout, nil
+}
+
+// offsetOfCharacterWrittenAs returns the offset and length of the character in text that writtenRune was written
+// from, which is the first character at or after offset, after any whitespace that was not written. An invalid
+// byte is written as U+FFFD or as the same byte (see InvalidUTF8Policy).
+func offsetOfCharacterWrittenAs(writtenRune rune, text string, offset int) (characterOffset int, characterLength int, characterWasFound bool) {
+	for offset < len(text) {
+		r, length := utf8.DecodeRuneInString(text[offset:])
+
+		if unicode.IsSpace(r) {
+			if writtenRune == ' ' {
+				return offset, length, true
+			}
+
+			offset += length
+			continue
+		}
+
+		if r == writtenRune || (r == utf8.RuneError && length == 1 && (writtenRune == utf8.RuneError || writtenRune == rawByteRune(text[offset]))) {
+			return offset, length, true
+		}
+
+		return 0, 0, false
+	}
+
+	return 0, 0, false
+}
+
+// Text returns the text that was wrapped. Positions in the text are byte offsets in it.
+func (layout *Layout) Text() string {
+	return layout.text
+}
+
+// Rows returns the rows of the wrapped text, which are the wrapped text split at the line break sequence. Each
+// row includes its indent. The returned slice is used by the Layout, so it must not be changed.
+func (layout *Layout) Rows() []string {
+	return layout.rows
+}
+
+// SourceToVisual returns the position in the rows of the character at byteOffset in Text(). If byteOffset is in
+// the middle of a character, the position is that of the character. A character that is not written to the wrapped
+// text, and byteOffset len(Text()), are at the position after the last character that is written before them (or
+// at the start of the first row, after the indent, if there is none). A character that is zero columns wide is at
+// the same position as the character after it. If byteOffset is less than 0, or more than len(Text()), it is
+// treated as 0 or len(Text()).
+func (layout *Layout) SourceToVisual(byteOffset int) VisualPosition {
+	indexOfCharacterAfterTheOffset := sort.Search(len(layout.characters), func(i int) bool {
+		return layout.characters[i].byteOffset > byteOffset
+	})
+
+	if indexOfCharacterAfterTheOffset == 0 {
+		return VisualPosition{Row: 0, Column: layout.columnAfterTheIndent[0]}
+	}
+
+	character := layout.characters[indexOfCharacterAfterTheOffset-1]
+	if byteOffset < character.byteOffset+character.byteLength {
+		return VisualPosition{Row: character.row, Column: character.column}
+	}
+
+	return VisualPosition{Row: character.row, Column: character.column + character.width}
+}
+
+// VisualToSource returns the byte offset in Text() of the character at position in the rows. If the position is in
+// a character that is more than one column wide, the offset is that of the character. A position before the first
+// character of a row (in its indent) is at the first character, and a position after the last character of a row
+// is after the last character, which is at the start of any whitespace that was not written where the row was
+// broken. A position before the first row is at offset 0, and a position after the last row is at len(Text()).
+func (layout *Layout) VisualToSource(position VisualPosition) int {
+	if position.Row < 0 {
+		return 0
+	} else if position.Row >= len(layout.rows) {
+		return len(layout.text)
+	}
+
+	charactersInTheRow := layout.characters[layout.indexOfFirstCharacter[position.Row]:layout.indexOfFirstCharacter[position.Row+1]]
+	if len(charactersInTheRow) == 0 {
+		return 0
+	}
+
+	for _, character := range charactersInTheRow {
+		if position.Column < character.column+character.width {
+			return character.byteOffset
+		}
+	}
+
+	lastCharacterInTheRow := charactersInTheRow[len(charactersInTheRow)-1]
+	return lastCharacterInTheRow.byteOffset + lastCharacterInTheRow.byteLength
+}
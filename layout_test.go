@@ -0,0 +1,324 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/blorticus-go/text"
+)
+
+type sourceAndVisualPositions struct {
+	byteOffset     int
+	visualPosition text.VisualPosition
+}
+
+type LayoutTestCase struct {
+	testName                               string
+	wrapper                                *text.Wrapper
+	unwrappedString                        string
+	expectedRows                           []string
+	expectedVisualPositionsOfSourceOffsets []sourceAndVisualPositions
+	expectedSourceOffsetsOfVisualPositions []sourceAndVisualPositions
+}
+
+func (testCase *LayoutTestCase) RunTest() error {
+	layout, err := testCase.wrapper.LayOutStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if strings.Join(layout.Rows(), "|") != strings.Join(testCase.expectedRows, "|") {
+		return fmt.Errorf("[%s] expected rows = (%q), got = (%q)", testCase.testName, testCase.expectedRows, layout.Rows())
+	}
+
+	for _, expected := range testCase.expectedVisualPositionsOfSourceOffsets {
+		if visualPosition := layout.SourceToVisual(expected.byteOffset); visualPosition != expected.visualPosition {
+			return fmt.Errorf("[%s] for byte offset %d, expected = (%+v), got = (%+v)", testCase.testName, expected.byteOffset, expected.visualPosition, visualPosition)
+		}
+	}
+
+	for _, expected := range testCase.expectedSourceOffsetsOfVisualPositions {
+		if byteOffset := layout.VisualToSource(expected.visualPosition); byteOffset != expected.byteOffset {
+			return fmt.Errorf("[%s] for position (%+v), expected = (%d), got = (%d)", testCase.testName, expected.visualPosition, expected.byteOffset, byteOffset)
+		}
+	}
+
+	return nil
+}
+
+func TestLayout(t *testing.T) {
+	testCases := []*LayoutTestCase{
+		{
+			testName:        "empty text",
+			wrapper:         text.NewWrapper().UsingRowWidth(10),
+			unwrappedString: "",
+			expectedRows:    []string{""},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: 1, Column: 0}},
+			},
+		},
+		{
+			testName:        "whitespace is dropped at the start, at row breaks and at the end",
+			wrapper:         text.NewWrapper().UsingRowWidth(10),
+			unwrappedString: "  one two\tthree \n four  ",
+			expectedRows:    []string{"one two", "three", "four"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+				{byteOffset: 1, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 5, visualPosition: text.VisualPosition{Row: 0, Column: 3}},
+				{byteOffset: 6, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+				{byteOffset: 9, visualPosition: text.VisualPosition{Row: 0, Column: 7}},
+				{byteOffset: 10, visualPosition: text.VisualPosition{Row: 1, Column: 0}},
+				{byteOffset: 15, visualPosition: text.VisualPosition{Row: 1, Column: 5}},
+				{byteOffset: 17, visualPosition: text.VisualPosition{Row: 1, Column: 5}},
+				{byteOffset: 18, visualPosition: text.VisualPosition{Row: 2, Column: 0}},
+				{byteOffset: 22, visualPosition: text.VisualPosition{Row: 2, Column: 4}},
+				{byteOffset: 24, visualPosition: text.VisualPosition{Row: 2, Column: 4}},
+				{byteOffset: 100, visualPosition: text.VisualPosition{Row: 2, Column: 4}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+				{byteOffset: 5, visualPosition: text.VisualPosition{Row: 0, Column: 3}},
+				{byteOffset: 6, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+				{byteOffset: 9, visualPosition: text.VisualPosition{Row: 0, Column: 7}},
+				{byteOffset: 9, visualPosition: text.VisualPosition{Row: 0, Column: 20}},
+				{byteOffset: 10, visualPosition: text.VisualPosition{Row: 1, Column: 0}},
+				{byteOffset: 15, visualPosition: text.VisualPosition{Row: 1, Column: 5}},
+				{byteOffset: 22, visualPosition: text.VisualPosition{Row: 2, Column: 9}},
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: -1, Column: 0}},
+				{byteOffset: 24, visualPosition: text.VisualPosition{Row: 3, Column: 0}},
+			},
+		},
+		{
+			testName:        "whitespace between words is written as spaces",
+			wrapper:         text.NewWrapper().UsingRowWidth(20),
+			unwrappedString: "one\t\ntwo　three",
+			expectedRows:    []string{"one  two three"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 3, visualPosition: text.VisualPosition{Row: 0, Column: 3}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+				{byteOffset: 5, visualPosition: text.VisualPosition{Row: 0, Column: 5}},
+				{byteOffset: 8, visualPosition: text.VisualPosition{Row: 0, Column: 8}},
+				{byteOffset: 9, visualPosition: text.VisualPosition{Row: 0, Column: 8}},
+				{byteOffset: 11, visualPosition: text.VisualPosition{Row: 0, Column: 9}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+				{byteOffset: 8, visualPosition: text.VisualPosition{Row: 0, Column: 8}},
+				{byteOffset: 11, visualPosition: text.VisualPosition{Row: 0, Column: 9}},
+			},
+		},
+		{
+			testName:        "indents and a word that is cut",
+			wrapper:         text.NewWrapper().UsingRowWidth(8).UsingIndentStringForFirstRow("* ").UsingIndentStringForRowsAfterTheFirst("  "),
+			unwrappedString: "a abcdefghij",
+			expectedRows:    []string{"* a", "  abcdef", "  ghij"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 1, visualPosition: text.VisualPosition{Row: 0, Column: 3}},
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 1, Column: 2}},
+				{byteOffset: 8, visualPosition: text.VisualPosition{Row: 2, Column: 2}},
+				{byteOffset: 12, visualPosition: text.VisualPosition{Row: 2, Column: 6}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 0, visualPosition: text.VisualPosition{Row: 0, Column: 0}},
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 1, Column: 1}},
+				{byteOffset: 7, visualPosition: text.VisualPosition{Row: 1, Column: 7}},
+				{byteOffset: 8, visualPosition: text.VisualPosition{Row: 1, Column: 8}},
+				{byteOffset: 8, visualPosition: text.VisualPosition{Row: 2, Column: 2}},
+			},
+		},
+		{
+			testName:        "wide and zero width characters",
+			wrapper:         text.NewWrapper().UsingRowWidth(10).UsingMeasurer(text.EastAsianNarrowMeasurer),
+			unwrappedString: "日本語 e\u0301t",
+			expectedRows:    []string{"日本語 e\u0301t"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 3, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 9, visualPosition: text.VisualPosition{Row: 0, Column: 6}},
+				{byteOffset: 10, visualPosition: text.VisualPosition{Row: 0, Column: 7}},
+				{byteOffset: 11, visualPosition: text.VisualPosition{Row: 0, Column: 8}},
+				{byteOffset: 13, visualPosition: text.VisualPosition{Row: 0, Column: 8}},
+				{byteOffset: 14, visualPosition: text.VisualPosition{Row: 0, Column: 9}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 3, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 3, visualPosition: text.VisualPosition{Row: 0, Column: 3}},
+				{byteOffset: 10, visualPosition: text.VisualPosition{Row: 0, Column: 7}},
+				{byteOffset: 13, visualPosition: text.VisualPosition{Row: 0, Column: 8}},
+				{byteOffset: 14, visualPosition: text.VisualPosition{Row: 0, Column: 9}},
+			},
+		},
+		{
+			testName:        "invalid UTF-8 that is replaced",
+			wrapper:         text.NewWrapper().UsingRowWidth(10).UsingInvalidUTF8Policy(text.ReplaceInvalidUTF8),
+			unwrappedString: "a\xffb c",
+			expectedRows:    []string{"a�b c"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 1, visualPosition: text.VisualPosition{Row: 0, Column: 1}},
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+			},
+		},
+		{
+			testName:        "invalid UTF-8 that is passed through",
+			wrapper:         text.NewWrapper().UsingRowWidth(10).UsingInvalidUTF8Policy(text.PassInvalidUTF8Through),
+			unwrappedString: "a\xffb c",
+			expectedRows:    []string{"a\xffb c"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 2}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 1, visualPosition: text.VisualPosition{Row: 0, Column: 1}},
+			},
+		},
+		{
+			testName:        "normalized text",
+			wrapper:         text.NewWrapper().UsingRowWidth(10).UsingInputNormalization(text.NormalizeToNFC),
+			unwrappedString: "été x",
+			expectedRows:    []string{"été x"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 1}},
+				{byteOffset: 6, visualPosition: text.VisualPosition{Row: 0, Column: 4}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 1}},
+			},
+		},
+		{
+			testName:        "font metrics columns are in 64ths of a point",
+			wrapper:         text.NewWrapper().UsingFontMetrics(text.NewFontMetricsTable(5).UsingAdvanceWidth('i', 2.5).UsingKerning('a', 'b', -1), 26),
+			unwrappedString: "iii ab cd",
+			expectedRows:    []string{"iii ab", "cd"},
+			expectedVisualPositionsOfSourceOffsets: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 320}},
+				{byteOffset: 4, visualPosition: text.VisualPosition{Row: 0, Column: 800}},
+				{byteOffset: 5, visualPosition: text.VisualPosition{Row: 0, Column: 1120}},
+				{byteOffset: 6, visualPosition: text.VisualPosition{Row: 0, Column: 1376}},
+				{byteOffset: 7, visualPosition: text.VisualPosition{Row: 1, Column: 0}},
+			},
+			expectedSourceOffsetsOfVisualPositions: []sourceAndVisualPositions{
+				{byteOffset: 2, visualPosition: text.VisualPosition{Row: 0, Column: 340}},
+				{byteOffset: 5, visualPosition: text.VisualPosition{Row: 0, Column: 1200}},
+				{byteOffset: 6, visualPosition: text.VisualPosition{Row: 0, Column: 1376}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestLayoutOfGeneratedText(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"a", "to", "the", "quick", "brown", "jumped", "über", "日本語", "é", "wrapping", "incomprehensibilities"}
+	separators := []string{" ", " ", " ", "\n", "  ", "\t", "\n\n", " 　 ", "\r\n"}
+
+	for _, wrapper := range []*text.Wrapper{
+		text.NewWrapper().UsingRowWidth(8),
+		text.NewWrapper().UsingRowWidth(20).UsingIndentStringForFirstRow("> ").UsingIndentStringForRowsAfterTheFirst("    "),
+		text.NewWrapper().UsingRowWidth(15).UsingMeasurer(text.EastAsianNarrowMeasurer),
+		text.NewWrapper().UsingRowWidth(12).UsingWordSegmenter(text.NewDictionaryWordSegmenter()),
+		text.NewWrapper().UsingFontMetrics(text.NewFontMetricsTable(5), 60),
+		text.NewWrapper().UsingRowWidth(7).UsingKinsokuAdjustment(text.KinsokuPushOut),
+		text.NewWrapper().UsingRowWidth(7).UsingKinsokuAdjustment(text.KinsokuPushIn),
+	} {
+		for textNumber := 0; textNumber < 50; textNumber++ {
+			var textBuilder strings.Builder
+			for numberOfWords := random.Intn(40); numberOfWords > 0; numberOfWords-- {
+				textBuilder.WriteString(separators[random.Intn(len(separators))])
+				textBuilder.WriteString(words[random.Intn(len(words))])
+			}
+
+			if err := errorUnlessTheLayoutIsConsistent(wrapper, textBuilder.String()); err != nil {
+				t.Fatalf("for text (%q): %s", textBuilder.String(), err)
+			}
+		}
+	}
+}
+
+// errorUnlessTheLayoutIsConsistent checks that the rows of the Layout of unwrappedString are the wrapped text, that
+// positions in the text move forward through the rows, and that each character that is written to the rows, and is
+// wider than zero columns, is mapped to its position in the rows and back.
+func errorUnlessTheLayoutIsConsistent(wrapper *text.Wrapper, unwrappedString string) error {
+	wrappedText, err := wrapper.WrapStringText(unwrappedString)
+	if err != nil {
+		return err
+	}
+
+	layout, err := wrapper.LayOutStringText(unwrappedString)
+	if err != nil {
+		return err
+	}
+
+	if strings.Join(layout.Rows(), "\n") != wrappedText {
+		return fmt.Errorf("expected rows = (%q), got = (%q)", wrappedText, layout.Rows())
+	}
+
+	previousPosition := layout.SourceToVisual(0)
+	for byteOffset := 1; byteOffset <= len(layout.Text()); byteOffset++ {
+		position := layout.SourceToVisual(byteOffset)
+		if position.Row < previousPosition.Row || (position.Row == previousPosition.Row && position.Column < previousPosition.Column) {
+			return fmt.Errorf("byte offset %d is at (%+v), which is before the position of the byte before it (%+v)", byteOffset, position, previousPosition)
+		}
+
+		previousPosition = position
+	}
+
+	for rowIndex, row := range layout.Rows() {
+		for column := 0; column <= wrapper.StringWidth(row)*5+1; column++ {
+			byteOffset := layout.VisualToSource(text.VisualPosition{Row: rowIndex, Column: column})
+			if byteOffset >= len(layout.Text()) {
+				continue
+			}
+
+			position := layout.SourceToVisual(byteOffset)
+			// the position after the last character of a row is the position of the first character of the next row,
+			// if there was no whitespace between them
+			if position.Row != rowIndex && !(position.Row == rowIndex+1 && layout.VisualToSource(text.VisualPosition{Row: rowIndex + 1, Column: 0}) == byteOffset) {
+				return fmt.Errorf("position (row %d, column %d) is at byte offset %d, which is at (%+v)", rowIndex, column, byteOffset, position)
+			}
+
+			if r, _ := utf8.DecodeRuneInString(layout.Text()[byteOffset:]); position.Row == rowIndex && wrapper.StringWidth(string(r)) > 0 && layout.VisualToSource(position) != byteOffset {
+				return fmt.Errorf("byte offset %d is at (%+v), which is at byte offset %d", byteOffset, position, layout.VisualToSource(position))
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestLayoutIsNotAvailable(t *testing.T) {
+	for _, wrapper := range []*text.Wrapper{
+		text.NewWrapper().UsingBidiMode(text.BidiVisualOrder),
+		text.NewWrapper().UsingMaximumRows(2),
+		text.NewWrapper().UsingIndentsDetectedFromInput(),
+	} {
+		if _, err := wrapper.LayOutStringText("one two"); !errors.Is(err, text.ErrLayoutIsNotAvailable) {
+			t.Errorf("expected error = (%v), got = (%v)", text.ErrLayoutIsNotAvailable, err)
+		}
+	}
+
+	if _, err := text.NewWrapper().LayOutStringText("one \xff two"); !errors.As(err, new(*text.InvalidUTF8Error)) {
+		t.Errorf("expected an InvalidUTF8Error, got = (%v)", err)
+	}
+}
@@ -0,0 +1,117 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// ErrLimitExceeded is returned (wrapped in a WrapError) when the input or the wrapped text is larger than one of the
+// limits set by ChangeInputByteLimitTo(), ChangeOutputRowLimitTo() or ChangeWordLengthLimitTo(). Use errors.Is()
+// to test for it.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ChangeInputByteLimitTo sets the largest number of bytes of UTF-8 text that the Wrapper will read from its input
+// (after any decoding from another encoding). If the input is longer, wrapping stops with ErrLimitExceeded. The
+// Wrapper reads no more than one byte past the limit. A value of 0 (the default) means there is no limit.
+func (wrapper *Wrapper) ChangeInputByteLimitTo(numberOfBytes uint) *Wrapper {
+	wrapper.inputByteLimit = numberOfBytes
+	return wrapper
+}
+
+// UsingInputByteLimit is the same as ChangeInputByteLimitTo(), but provides a more readable name if this is chained
+// with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingInputByteLimit(1 << 20).UsingOutputRowLimit(10000).UsingWordLengthLimit(1000)
+func (wrapper *Wrapper) UsingInputByteLimit(numberOfBytes uint) *Wrapper {
+	return wrapper.ChangeInputByteLimitTo(numberOfBytes)
+}
+
+// ChangeOutputRowLimitTo sets the largest number of rows that wrapping may produce. If the wrapped text would have
+// more rows, wrapping stops with ErrLimitExceeded. Unlike ChangeMaximumRowsTo(), which drops rows from the
+// wrapped text, this limit is checked as the text is wrapped, before any rows are dropped. A value of 0 (the
+// default) means there is no limit.
+func (wrapper *Wrapper) ChangeOutputRowLimitTo(numberOfRows uint) *Wrapper {
+	wrapper.outputRowLimit = numberOfRows
+	return wrapper
+}
+
+// UsingOutputRowLimit is the same as ChangeOutputRowLimitTo(), but provides a more readable name if this is chained
+// with the constructor.
+func (wrapper *Wrapper) UsingOutputRowLimit(numberOfRows uint) *Wrapper {
+	return wrapper.ChangeOutputRowLimitTo(numberOfRows)
+}
+
+// ChangeWordLengthLimitTo sets the largest number of runes in a word (a run of non-whitespace characters, or a
+// word found by the WordSegmenter). Words that are longer than a row are still broken across rows as usual, but if
+// a word is longer than this limit, it is truncated to numberOfRunes runes and wrapping stops with ErrLimitExceeded.
+// The wrapped text returned with the error ends with the truncated word, and the position in the error is that of
+// the first rune that was dropped. A value of 0 (the default) means there is no limit.
+func (wrapper *Wrapper) ChangeWordLengthLimitTo(numberOfRunes uint) *Wrapper {
+	wrapper.wordLengthLimit = numberOfRunes
+	return wrapper
+}
+
+// UsingWordLengthLimit is the same as ChangeWordLengthLimitTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (wrapper *Wrapper) UsingWordLengthLimit(numberOfRunes uint) *Wrapper {
+	return wrapper.ChangeWordLengthLimitTo(numberOfRunes)
+}
+
+// inputLimitingReaderFor returns a reader that reads from reader but fails with ErrLimitExceeded if reader has more
+// bytes than the input byte limit, or reader itself if there is no limit.
+func (wrapper *Wrapper) inputLimitingReaderFor(reader io.Reader) io.Reader {
+	if wrapper.inputByteLimit == 0 {
+		return reader
+	}
+
+	return &inputLimitingReader{source: reader, bytesRemaining: int64(wrapper.inputByteLimit), limit: wrapper.inputByteLimit}
+}
+
+type inputLimitingReader struct {
+	source         io.Reader
+	bytesRemaining int64
+	limit          uint
+}
+
+func (reader *inputLimitingReader) Read(p []byte) (int, error) {
+	if reader.bytesRemaining < 0 {
+		return 0, fmt.Errorf("%w: input is longer than %d bytes", ErrLimitExceeded, reader.limit)
+	}
+
+	// one byte more than the limit is read, to find out whether the input is longer than the limit
+	if int64(len(p)) > reader.bytesRemaining+1 {
+		p = p[:reader.bytesRemaining+1]
+	}
+
+	bytesRead, err := reader.source.Read(p)
+	reader.bytesRemaining -= int64(bytesRead)
+
+	if reader.bytesRemaining < 0 {
+		return bytesRead + int(reader.bytesRemaining), nil
+	}
+
+	return bytesRead, err
+}
+
+// rowLimitError returns the error for exceeding the output row limit, if adding another row would exceed it.
+func (wrapper *Wrapper) rowLimitError(numberOfRowsBeforeTheNewRow int) error {
+	if wrapper.outputRowLimit != 0 && uint(numberOfRowsBeforeTheNewRow) >= wrapper.outputRowLimit {
+		return fmt.Errorf("%w: wrapped text has more than %d rows", ErrLimitExceeded, wrapper.outputRowLimit)
+	}
+
+	return nil
+}
+
+// lengthOfWordEndingWith returns the number of runes in the word that r is part of, up to and including r, where
+// lengthBeforeR is the value returned for the character before r.
+func lengthOfWordEndingWith(r rune, startsAWord bool, lengthBeforeR uint) uint {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case startsAWord:
+		return 1
+	default:
+		return lengthBeforeR + 1
+	}
+}
@@ -0,0 +1,167 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type LimitTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	unwrappedString       string
+	expectedWrappedString string
+	expectLimitExceeded   bool
+}
+
+func (testCase *LimitTestCase) RunTest() error {
+	for _, useAReader := range []bool{false, true} {
+		var wrappedString string
+		var err error
+
+		if useAReader {
+			wrappedString, err = testCase.wrapper.WrapUTF8TextFromAReader(strings.NewReader(testCase.unwrappedString))
+		} else {
+			wrappedString, err = testCase.wrapper.WrapStringText(testCase.unwrappedString)
+		}
+
+		if testCase.expectLimitExceeded {
+			if !errors.Is(err, text.ErrLimitExceeded) {
+				return fmt.Errorf("[%s] (reader = %t) expected ErrLimitExceeded, got = (%v)", testCase.testName, useAReader, err)
+			}
+		} else if err != nil {
+			return fmt.Errorf("[%s] (reader = %t) unexpected error: %s", testCase.testName, useAReader, err)
+		}
+
+		if wrappedString != testCase.expectedWrappedString {
+			return fmt.Errorf("[%s] (reader = %t) expected = (%q), got = (%q)", testCase.testName, useAReader, testCase.expectedWrappedString, wrappedString)
+		}
+	}
+
+	return nil
+}
+
+func TestWrapWithLimits(t *testing.T) {
+	testCases := []*LimitTestCase{
+		{
+			testName:              "input at the byte limit",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingInputByteLimit(13),
+			unwrappedString:       "one two three",
+			expectedWrappedString: "one two\nthree",
+		},
+		{
+			testName:              "input over the byte limit",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingInputByteLimit(12),
+			unwrappedString:       "one two three",
+			expectedWrappedString: "one two",
+			expectLimitExceeded:   true,
+		},
+		{
+			testName:              "multi-byte input over the byte limit",
+			wrapper:               text.NewWrapper().UsingInputByteLimit(8),
+			unwrappedString:       "日本 語",
+			expectedWrappedString: "日本",
+			expectLimitExceeded:   true,
+		},
+		{
+			testName:              "rows at the row limit",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingOutputRowLimit(2),
+			unwrappedString:       "one two three",
+			expectedWrappedString: "one two\nthree",
+		},
+		{
+			testName:              "rows over the row limit",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingOutputRowLimit(2),
+			unwrappedString:       "one two three four five",
+			expectedWrappedString: "one two\nthree",
+			expectLimitExceeded:   true,
+		},
+		{
+			testName:              "row limit is checked before rows are dropped",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingOutputRowLimit(2).UsingMaximumRows(1),
+			unwrappedString:       "one two three four five",
+			expectedWrappedString: "one two\nthree",
+			expectLimitExceeded:   true,
+		},
+		{
+			testName:              "word at the word length limit is broken across rows",
+			wrapper:               text.NewWrapper().UsingRowWidth(4).UsingWordLengthLimit(6),
+			unwrappedString:       "ab abcdef",
+			expectedWrappedString: "ab\nabcd\nef",
+		},
+		{
+			testName:              "word over the word length limit is truncated",
+			wrapper:               text.NewWrapper().UsingRowWidth(4).UsingWordLengthLimit(6),
+			unwrappedString:       "ab abcdefg",
+			expectedWrappedString: "ab\nabcd\nef",
+			expectLimitExceeded:   true,
+		},
+		{
+			testName:              "wrapping stops after a truncated word",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingWordLengthLimit(4),
+			unwrappedString:       "one threeeeeeeeeee four five",
+			expectedWrappedString: "one thre",
+			expectLimitExceeded:   true,
+		},
+		{
+			testName:              "segmented words are counted separately",
+			wrapper:               text.NewWrapper().UsingRowWidth(20).UsingWordLengthLimit(5).UsingWordSegmenter(text.NewDictionaryWordSegmenter()),
+			unwrappedString:       "ภาษาไทยง่ายนิดเดียว",
+			expectedWrappedString: "ภาษาไทยง่ายนิดเดียว",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestWordLengthLimitReportsTheTruncatedWord(t *testing.T) {
+	wrappedText, err := text.NewWrapper().UsingRowWidth(8).UsingWordLengthLimit(4).WrapStringText("one two\nthreeeee four")
+	if wrappedText != "one two\nthre" {
+		t.Errorf("expected = (%q), got = (%q)", "one two\nthre", wrappedText)
+	}
+
+	expectedWrapError := &text.WrapError{
+		Err:        text.ErrLimitExceeded,
+		ByteOffset: 12,
+		RuneOffset: 12,
+		Line:       2,
+		Row:        2,
+	}
+
+	if err := errorUnlessTheExpectedWrapError(expectedWrapError, err); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	source    io.Reader
+	bytesRead int
+}
+
+func (reader *countingReader) Read(p []byte) (int, error) {
+	bytesRead, err := reader.source.Read(p)
+	reader.bytesRead += bytesRead
+	return bytesRead, err
+}
+
+func TestInputByteLimitStopsReading(t *testing.T) {
+	reader := &countingReader{source: strings.NewReader(strings.Repeat("word ", 100000))}
+
+	_, err := text.NewWrapper().UsingInputByteLimit(1000).WrapUTF8TextFromAReader(reader)
+	if !errors.Is(err, text.ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got = (%v)", err)
+	}
+
+	if reader.bytesRead > 1001 {
+		t.Errorf("expected no more than 1001 bytes to be read, got = %d", reader.bytesRead)
+	}
+}
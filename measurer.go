@@ -0,0 +1,151 @@
+package text
+
+import (
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Measurer reports the number of columns that a rune occupies. The Wrapper uses its Measurer for all column
+// accounting: for the indent strings, the ellipsis and every rune of every word. Whitespace is not measured,
+// because the Wrapper converts each whitespace rune that it emits into a single ASCII space (one column).
+// A Measurer may return 0 for runes that are drawn as part of the preceding rune (such as combining marks),
+// so that a grapheme cluster measures the same as its base character. The Wrapper never separates a rune with
+// zero width from the rune before it.
+type Measurer interface {
+	RuneWidth(r rune) int
+}
+
+// WidthFunc is a function that implements Measurer.
+type WidthFunc func(r rune) int
+
+// RuneWidth returns widthFunc(r).
+func (widthFunc WidthFunc) RuneWidth(r rune) int {
+	return widthFunc(r)
+}
+
+type runeCountMeasurer struct{}
+
+func (measurer runeCountMeasurer) RuneWidth(r rune) int {
+	return 1
+}
+
+type eastAsianWidthMeasurer struct {
+	widthOfAmbiguousRunes int
+}
+
+func (measurer eastAsianWidthMeasurer) RuneWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc) || (r >= 0x1160 && r <= 0x11ff) {
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	case width.EastAsianAmbiguous:
+		return measurer.widthOfAmbiguousRunes
+	default:
+		return 1
+	}
+}
+
+var (
+	// RuneCountMeasurer counts every rune as one column. This is the Measurer used by a Wrapper by default.
+	RuneCountMeasurer Measurer = runeCountMeasurer{}
+
+	// EastAsianNarrowMeasurer uses the East Asian Width property (Unicode Standard Annex #11) to measure runes
+	// the way most terminals do. Wide and Fullwidth runes (for example, CJK ideographs, Hangul syllables and most
+	// emoji) are two columns. Ambiguous runes (for example, Greek and Cyrillic letters and many symbols) are
+	// one column. Nonspacing and enclosing marks, format characters, control characters and Hangul medial
+	// vowels and final consonants are zero columns. All other runes are one column.
+	EastAsianNarrowMeasurer Measurer = eastAsianWidthMeasurer{widthOfAmbiguousRunes: 1}
+
+	// EastAsianWideMeasurer is the same as EastAsianNarrowMeasurer, except that Ambiguous runes are two columns.
+	// This matches terminals that are configured to draw ambiguous width characters as wide, which is common in
+	// CJK locales.
+	EastAsianWideMeasurer Measurer = eastAsianWidthMeasurer{widthOfAmbiguousRunes: 2}
+)
+
+// ChangeMeasurerTo changes the Measurer used for column accounting. The default is RuneCountMeasurer.
+func (wrapper *Wrapper) ChangeMeasurerTo(measurer Measurer) *Wrapper {
+	if measurer == nil {
+		panic("Measurer must not be nil")
+	}
+
+	previousMeasurer := wrapper.measurer
+	wrapper.measurer = measurer
+
+	if wrapper.widthOfRunes(wrapper.initialLineIndentString) >= int(wrapper.rowWidthInWidthUnits()) || wrapper.widthOfRunes(wrapper.subsequentLinesIndentString) >= int(wrapper.rowWidthInWidthUnits()) {
+		wrapper.measurer = previousMeasurer
+		panic("RowWidth must be larger than row indent string")
+	}
+
+	return wrapper
+}
+
+// UsingMeasurer is the same as ChangeMeasurerTo(), but provides a more readable name if this is chained with the
+// constructor, as in:
+//    wrapper := text.NewWrapper().UsingMeasurer(text.EastAsianNarrowMeasurer).UsingRowWidth(40)
+func (wrapper *Wrapper) UsingMeasurer(measurer Measurer) *Wrapper {
+	return wrapper.ChangeMeasurerTo(measurer)
+}
+
+// noPreviousRune is passed to advanceOf() for the first rune in a row, which is not kerned.
+const noPreviousRune rune = -1
+
+// runeAdvancer is the width accounting used by the wrapping engine. advanceOf returns the width of r when it
+// follows previousRune in the same row.
+type runeAdvancer interface {
+	advanceOf(previousRune rune, r rune) int
+}
+
+// measurerAdvancer uses a Measurer for width accounting. Every whitespace rune is one column, because each
+// whitespace rune in wrapped text is emitted as a single ASCII space. Every invalid byte that is passed through
+// is also one column.
+type measurerAdvancer struct {
+	measurer Measurer
+}
+
+func (advancer measurerAdvancer) advanceOf(previousRune rune, r rune) int {
+	if unicode.IsSpace(r) || isRawByteRune(r) {
+		return 1
+	}
+
+	return advancer.measurer.RuneWidth(r)
+}
+
+func (wrapper *Wrapper) advancer() runeAdvancer {
+	if wrapper.fontMetrics != nil {
+		return fontMetricsAdvancer{metrics: wrapper.fontMetrics}
+	}
+
+	return measurerAdvancer{measurer: wrapper.measurer}
+}
+
+// columnAdvancer is the width accounting used for text that is laid out in monospace columns, which ignores
+// any font metrics.
+func (wrapper *Wrapper) columnAdvancer() runeAdvancer {
+	return measurerAdvancer{measurer: wrapper.measurer}
+}
+
+func (wrapper *Wrapper) widthOfRunes(runes []rune) int {
+	return widthOfRunesUsing(wrapper.advancer(), runes)
+}
+
+func widthOfRunesUsing(advancer runeAdvancer, runes []rune) int {
+	return widthOfRunesAfterUsing(advancer, noPreviousRune, runes)
+}
+
+func widthOfRunesAfterUsing(advancer runeAdvancer, previousRune rune, runes []rune) int {
+	widthOfAllRunes := 0
+	for _, r := range runes {
+		widthOfAllRunes += advancer.advanceOf(previousRune, r)
+		previousRune = r
+	}
+
+	return widthOfAllRunes
+}
+
+func widthOfStringUsing(advancer runeAdvancer, s string) int {
+	return widthOfRunesUsing(advancer, []rune(s))
+}
@@ -0,0 +1,195 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type MeasurerTestCase struct {
+	testName           string
+	measurer           text.Measurer
+	rowWidth           uint
+	maximumRows        uint
+	unwrappedString    string
+	expectedWrappedRow string
+}
+
+func (testCase *MeasurerTestCase) RunTest() error {
+	wrapper := text.NewWrapper().UsingMeasurer(testCase.measurer).UsingRowWidth(testCase.rowWidth).UsingMaximumRows(testCase.maximumRows)
+
+	wrappedString, err := wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedRow {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedRow, wrappedString)
+	}
+
+	return nil
+}
+
+func TestMeasurerRuneWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		measurer      text.Measurer
+		measurerName  string
+		r             rune
+		expectedWidth int
+	}{
+		{text.RuneCountMeasurer, "RuneCountMeasurer", '日', 1},
+		{text.RuneCountMeasurer, "RuneCountMeasurer", '\u0301', 1},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", 'a', 1},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", '日', 2},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", 'Ａ', 2},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", 'ｱ', 1},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", 'α', 1},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", '\u0301', 0},
+		{text.EastAsianNarrowMeasurer, "EastAsianNarrowMeasurer", '\u200b', 0},
+		{text.EastAsianWideMeasurer, "EastAsianWideMeasurer", 'α', 2},
+		{text.EastAsianWideMeasurer, "EastAsianWideMeasurer", '…', 2},
+		{text.EastAsianWideMeasurer, "EastAsianWideMeasurer", '日', 2},
+		{text.EastAsianWideMeasurer, "EastAsianWideMeasurer", 'a', 1},
+	} {
+		if width := testCase.measurer.RuneWidth(testCase.r); width != testCase.expectedWidth {
+			t.Errorf("[%s] for (%q) expected width = (%d), got = (%d)", testCase.measurerName, testCase.r, testCase.expectedWidth, width)
+		}
+	}
+}
+
+func TestWrapUsingMeasurer(t *testing.T) {
+	digitsAreTwoColumns := text.WidthFunc(func(r rune) int {
+		if r >= '0' && r <= '9' {
+			return 2
+		}
+		return 1
+	})
+
+	atSignIsFourColumns := text.WidthFunc(func(r rune) int {
+		if r == '@' {
+			return 4
+		}
+		return 1
+	})
+
+	testCases := []*MeasurerTestCase{
+		{
+			testName:           "wide characters with no spaces",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           10,
+			unwrappedString:    "日本語のテキストを折り返す",
+			expectedWrappedRow: "日本語のテ\nキストを折\nり返す",
+		},
+		{
+			testName:           "wide characters with an odd row width",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           9,
+			unwrappedString:    "日本語のテキスト",
+			expectedWrappedRow: "日本語の\nテキスト",
+		},
+		{
+			testName:           "mixed narrow and wide words",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           8,
+			unwrappedString:    "abc 日本語 def",
+			expectedWrappedRow: "abc\n日本語\ndef",
+		},
+		{
+			testName:           "ambiguous characters as narrow",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           7,
+			unwrappedString:    "αβγ δεζ",
+			expectedWrappedRow: "αβγ δεζ",
+		},
+		{
+			testName:           "ambiguous characters as wide",
+			measurer:           text.EastAsianWideMeasurer,
+			rowWidth:           7,
+			unwrappedString:    "αβγ δεζ",
+			expectedWrappedRow: "αβγ\nδεζ",
+		},
+		{
+			testName:           "combining marks are not separated from their character",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           3,
+			unwrappedString:    "e\u0301e\u0301e\u0301e\u0301",
+			expectedWrappedRow: "e\u0301e\u0301e\u0301\ne\u0301",
+		},
+		{
+			testName:           "wide character wider than the row",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           1,
+			unwrappedString:    "日本",
+			expectedWrappedRow: "日\n本",
+		},
+		{
+			testName:           "custom WidthFunc",
+			measurer:           digitsAreTwoColumns,
+			rowWidth:           6,
+			unwrappedString:    "ab 123 cd",
+			expectedWrappedRow: "ab\n123\ncd",
+		},
+		{
+			testName:           "character wider than the row after whitespace",
+			measurer:           atSignIsFourColumns,
+			rowWidth:           3,
+			unwrappedString:    "a @@ b",
+			expectedWrappedRow: "a\n@\n@\nb",
+		},
+		{
+			testName:           "truncation with wide characters and narrow ellipsis",
+			measurer:           text.EastAsianNarrowMeasurer,
+			rowWidth:           6,
+			maximumRows:        1,
+			unwrappedString:    "日本語のテキスト",
+			expectedWrappedRow: "日本…",
+		},
+		{
+			testName:           "truncation with wide characters and wide ellipsis",
+			measurer:           text.EastAsianWideMeasurer,
+			rowWidth:           7,
+			maximumRows:        1,
+			unwrappedString:    "日本語のテキスト",
+			expectedWrappedRow: "日本…",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestWrapperFitFunctionsUseMeasurer(t *testing.T) {
+	wrapper := text.NewWrapper().UsingMeasurer(text.EastAsianNarrowMeasurer)
+
+	testCases := []*FitTestCase{
+		{"Wrapper.Truncate", func() string { return wrapper.Truncate("日本語", 5, "…") }, "日本…"},
+		{"Wrapper.Pad", func() string { return wrapper.Pad("日本", 6, text.AlignRight) }, "  日本"},
+		{"Wrapper.Fit", func() string { return wrapper.Fit("日本語", 5, text.AlignLeft, "") }, "日本 "},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+
+	if width := wrapper.StringWidth("日本 e\u0301"); width != 6 {
+		t.Errorf("expected Wrapper.StringWidth() = (6), got = (%d)", width)
+	}
+}
+
+func TestChangeMeasurerPanicsWhenIndentNoLongerFits(t *testing.T) {
+	wrapper := text.NewWrapper().UsingRowWidth(4).UsingIndentStringForFirstRow("日本")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected ChangeMeasurerTo() to panic, but it did not")
+		}
+	}()
+
+	wrapper.ChangeMeasurerTo(text.EastAsianNarrowMeasurer)
+}
@@ -0,0 +1,380 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/blorticus-go/nibblers"
+)
+
+// maximumLengthOfASegmentedRun limits how many runes are read ahead to find the words in a run of text that is
+// written without spaces. A longer run is segmented in pieces, with a word boundary between the pieces.
+const maximumLengthOfASegmentedRun = 4096
+
+// maximumNumberOfCharactersThatCanBeUnread limits how many characters are kept so that they can be unread.
+const maximumNumberOfCharactersThatCanBeUnread = 1024
+
+// inputPosition is the position of a character in the text being wrapped.
+type inputPosition struct {
+	byteOffset int64
+	runeOffset int64
+	line       int
+}
+
+// positionReporter is implemented by a UTF8Nibbler that knows the position of the next character it will read.
+type positionReporter interface {
+	positionOfNextCharacter() inputPosition
+}
+
+type characterAndBreakOpportunity struct {
+	r            rune
+	startsAWord  bool
+	position     inputPosition
+	lengthOfWord uint
+}
+
+// breakOpportunityNibbler is the UTF8Nibbler used by the wrapping engine. It reads from another UTF8Nibbler,
+// and adds two things to it: any number of characters may be unread (up to a limit), and each character reports
+// whether it starts a word within a run of non-whitespace characters, which is a place where a row may be broken
+// even though there is no whitespace. Word starts are found by the WordSegmenter, if there is one.
+type breakOpportunityNibbler struct {
+	source            nibblers.UTF8Nibbler
+	wordSegmenter     WordSegmenter
+	wordLengthLimit   uint
+	readCharacters    []characterAndBreakOpportunity
+	pendingCharacters []characterAndBreakOpportunity
+	sourceError       error
+	runWasCutAtLimit  bool
+
+	// wordLengthLimitError is set when a word is truncated at the word length limit, and the input ends there.
+	wordLengthLimitError       error
+	positionOfTheTruncatedWord inputPosition
+}
+
+func newBreakOpportunityNibbler(source nibblers.UTF8Nibbler, wordSegmenter WordSegmenter, wordLengthLimit uint) *breakOpportunityNibbler {
+	return &breakOpportunityNibbler{
+		source:            source,
+		wordSegmenter:     wordSegmenter,
+		wordLengthLimit:   wordLengthLimit,
+		readCharacters:    make([]characterAndBreakOpportunity, 0, 128),
+		pendingCharacters: make([]characterAndBreakOpportunity, 0, 128),
+		sourceError:       nil,
+		runWasCutAtLimit:  false,
+
+		wordLengthLimitError:       nil,
+		positionOfTheTruncatedWord: inputPosition{},
+	}
+}
+
+// reset makes the nibbler the same as a new nibbler that reads from source, but keeps the memory allocated for its
+// characters.
+func (nibbler *breakOpportunityNibbler) reset(source nibblers.UTF8Nibbler, wordSegmenter WordSegmenter, wordLengthLimit uint) {
+	nibbler.source = source
+	nibbler.wordSegmenter = wordSegmenter
+	nibbler.wordLengthLimit = wordLengthLimit
+	nibbler.readCharacters = nibbler.readCharacters[:0]
+	nibbler.pendingCharacters = nibbler.pendingCharacters[:0]
+	nibbler.sourceError = nil
+	nibbler.runWasCutAtLimit = false
+	nibbler.wordLengthLimitError = nil
+	nibbler.positionOfTheTruncatedWord = inputPosition{}
+}
+
+// ReadCharacter reads the next character. If the character makes the word that it is in longer than the word length
+// limit, the word is truncated before it: ReadCharacter returns io.EOF from then on, so that the engine writes the
+// truncated word and stops, and wordLengthLimitError is set to the error that wrapping returns.
+func (nibbler *breakOpportunityNibbler) ReadCharacter() (rune, error) {
+	if len(nibbler.pendingCharacters) == 0 {
+		if err := nibbler.readMoreCharactersFromTheSource(); err != nil {
+			return 0, err
+		}
+	}
+
+	nextCharacter := nibbler.pendingCharacters[len(nibbler.pendingCharacters)-1]
+
+	lengthOfWordBeforeNextCharacter := uint(0)
+	if len(nibbler.readCharacters) > 0 {
+		lengthOfWordBeforeNextCharacter = nibbler.readCharacters[len(nibbler.readCharacters)-1].lengthOfWord
+	}
+
+	nextCharacter.lengthOfWord = lengthOfWordEndingWith(nextCharacter.r, nextCharacter.startsAWord, lengthOfWordBeforeNextCharacter)
+	if nibbler.wordLengthLimit != 0 && nextCharacter.lengthOfWord > nibbler.wordLengthLimit {
+		nibbler.wordLengthLimitError = fmt.Errorf("%w: word is longer than %d runes", ErrLimitExceeded, nibbler.wordLengthLimit)
+		nibbler.positionOfTheTruncatedWord = nextCharacter.position
+		nibbler.pendingCharacters = nibbler.pendingCharacters[:0]
+		nibbler.sourceError = io.EOF
+		return 0, io.EOF
+	}
+
+	nibbler.pendingCharacters = nibbler.pendingCharacters[:len(nibbler.pendingCharacters)-1]
+
+	if len(nibbler.readCharacters) == maximumNumberOfCharactersThatCanBeUnread {
+		copy(nibbler.readCharacters, nibbler.readCharacters[maximumNumberOfCharactersThatCanBeUnread/2:])
+		nibbler.readCharacters = nibbler.readCharacters[:maximumNumberOfCharactersThatCanBeUnread/2]
+	}
+	nibbler.readCharacters = append(nibbler.readCharacters, nextCharacter)
+
+	return nextCharacter.r, nil
+}
+
+// UnreadCharacter puts back the most recently read character that has not already been unread.
+func (nibbler *breakOpportunityNibbler) UnreadCharacter() error {
+	if len(nibbler.readCharacters) == 0 {
+		return errors.New("no character to unread")
+	}
+
+	nibbler.pendingCharacters = append(nibbler.pendingCharacters, nibbler.readCharacters[len(nibbler.readCharacters)-1])
+	nibbler.readCharacters = nibbler.readCharacters[:len(nibbler.readCharacters)-1]
+
+	return nil
+}
+
+// PeekAtNextCharacter returns the next character without reading it.
+func (nibbler *breakOpportunityNibbler) PeekAtNextCharacter() (rune, error) {
+	nextRune, err := nibbler.ReadCharacter()
+	if err != nil {
+		return 0, err
+	}
+
+	return nextRune, nibbler.UnreadCharacter()
+}
+
+// positionOfNextCharacter returns the position of the next character that will be read. If the source does not
+// report positions, every position is the start of the text.
+func (nibbler *breakOpportunityNibbler) positionOfNextCharacter() inputPosition {
+	if len(nibbler.pendingCharacters) > 0 {
+		return nibbler.pendingCharacters[len(nibbler.pendingCharacters)-1].position
+	}
+
+	return positionOfNextCharacterIn(nibbler.source)
+}
+
+func positionOfNextCharacterIn(source nibblers.UTF8Nibbler) inputPosition {
+	if sourceThatReportsPositions, isAPositionReporter := source.(positionReporter); isAPositionReporter {
+		return sourceThatReportsPositions.positionOfNextCharacter()
+	}
+
+	return inputPosition{line: 1}
+}
+
+// lastReadCharacterStartsAWord returns true if the most recently read character starts a word in a run of text
+// that was divided into words by the WordSegmenter (other than the first word in the run).
+func (nibbler *breakOpportunityNibbler) lastReadCharacterStartsAWord() bool {
+	if len(nibbler.readCharacters) == 0 {
+		return false
+	}
+
+	return nibbler.readCharacters[len(nibbler.readCharacters)-1].startsAWord
+}
+
+// readMoreCharactersFromTheSource adds at least one character to pendingCharacters, or returns the error from the
+// source. If the character is in a script that the WordSegmenter divides into words, the rest of the run of
+// characters in such scripts is read as well, so that the words in it can be found.
+func (nibbler *breakOpportunityNibbler) readMoreCharactersFromTheSource() error {
+	if nibbler.sourceError != nil {
+		return nibbler.sourceError
+	}
+
+	positionOfFirstRune := positionOfNextCharacterIn(nibbler.source)
+	firstRune, err := nibbler.source.ReadCharacter()
+	if err != nil {
+		nibbler.sourceError = err
+		return err
+	}
+
+	if nibbler.wordSegmenter == nil || !nibbler.wordSegmenter.Segments(firstRune) {
+		nibbler.pendingCharacters = append(nibbler.pendingCharacters, characterAndBreakOpportunity{r: firstRune, position: positionOfFirstRune})
+		nibbler.runWasCutAtLimit = false
+		return nil
+	}
+
+	run := []rune{firstRune}
+	positionsInRun := []inputPosition{positionOfFirstRune}
+	var characterAfterTheRun *characterAndBreakOpportunity
+
+	for len(run) < maximumLengthOfASegmentedRun {
+		positionOfNextRune := positionOfNextCharacterIn(nibbler.source)
+		nextRune, err := nibbler.source.ReadCharacter()
+		if err != nil {
+			nibbler.sourceError = err
+			break
+		}
+
+		if !nibbler.wordSegmenter.Segments(nextRune) {
+			characterAfterTheRun = &characterAndBreakOpportunity{r: nextRune, position: positionOfNextRune}
+			break
+		}
+
+		run = append(run, nextRune)
+		positionsInRun = append(positionsInRun, positionOfNextRune)
+	}
+
+	startsAWord := make([]bool, len(run))
+	startsAWord[0] = nibbler.runWasCutAtLimit
+	nibbler.runWasCutAtLimit = len(run) == maximumLengthOfASegmentedRun
+
+	for _, runeIndex := range nibbler.wordSegmenter.WordBoundaries(run) {
+		if runeIndex > 0 && runeIndex < len(run) {
+			startsAWord[runeIndex] = true
+		}
+	}
+
+	// pendingCharacters is a stack, so the characters are pushed in reverse order
+	if characterAfterTheRun != nil {
+		nibbler.pendingCharacters = append(nibbler.pendingCharacters, *characterAfterTheRun)
+	}
+
+	for runeIndex := len(run) - 1; runeIndex >= 0; runeIndex-- {
+		nibbler.pendingCharacters = append(nibbler.pendingCharacters, characterAndBreakOpportunity{r: run[runeIndex], startsAWord: startsAWord[runeIndex], position: positionsInRun[runeIndex]})
+	}
+
+	return nil
+}
+
+// sizeOfUTF8ReadBuffer is the number of bytes that a utf8ReaderNibbler reads from its source at a time.
+const sizeOfUTF8ReadBuffer = 4096
+
+// utf8ReaderNibbler is a UTF8Nibbler that decodes UTF-8 from an io.Reader, handling invalid bytes according to an
+// InvalidUTF8Policy. A multi-byte sequence may be split across Read()s of the source, and the source may return
+// its last bytes together with io.EOF.
+type utf8ReaderNibbler struct {
+	source                  io.Reader
+	invalidUTF8Policy       InvalidUTF8Policy
+	readBuffer              []byte
+	unconsumedBytes         []byte
+	sourceError             error
+	positionOfNextRune      inputPosition
+	positionOfLastReadRune  inputPosition
+	lastReadRune            rune
+	lastReadRuneWasUnread   bool
+	lastReadRuneCanBeUnread bool
+}
+
+func newUTF8ReaderNibbler(source io.Reader, invalidUTF8Policy InvalidUTF8Policy) *utf8ReaderNibbler {
+	readBuffer := make([]byte, sizeOfUTF8ReadBuffer)
+
+	return &utf8ReaderNibbler{
+		source:                  source,
+		invalidUTF8Policy:       invalidUTF8Policy,
+		readBuffer:              readBuffer,
+		unconsumedBytes:         readBuffer[:0],
+		sourceError:             nil,
+		positionOfNextRune:      inputPosition{byteOffset: 0, runeOffset: 0, line: 1},
+		lastReadRune:            0,
+		lastReadRuneWasUnread:   false,
+		lastReadRuneCanBeUnread: false,
+	}
+}
+
+// reset makes the nibbler the same as a new nibbler that reads from source, but keeps its read buffer.
+func (nibbler *utf8ReaderNibbler) reset(source io.Reader, invalidUTF8Policy InvalidUTF8Policy) {
+	nibbler.source = source
+	nibbler.invalidUTF8Policy = invalidUTF8Policy
+	nibbler.unconsumedBytes = nibbler.readBuffer[:0]
+	nibbler.sourceError = nil
+	nibbler.positionOfNextRune = inputPosition{byteOffset: 0, runeOffset: 0, line: 1}
+	nibbler.positionOfLastReadRune = inputPosition{}
+	nibbler.lastReadRune = 0
+	nibbler.lastReadRuneWasUnread = false
+	nibbler.lastReadRuneCanBeUnread = false
+}
+
+// ReadCharacter reads the next character. It returns an *InvalidUTF8Error if the next byte is not valid UTF-8 and
+// the policy is FailOnInvalidUTF8.
+func (nibbler *utf8ReaderNibbler) ReadCharacter() (rune, error) {
+	if nibbler.lastReadRuneWasUnread {
+		nibbler.lastReadRuneWasUnread = false
+		return nibbler.lastReadRune, nil
+	}
+
+	nibbler.lastReadRuneCanBeUnread = false
+
+	if err := nibbler.readFromTheSourceUntilTheNextRuneIsComplete(); err != nil {
+		return utf8.RuneError, err
+	}
+
+	nextRune, sizeOfRune := utf8.DecodeRune(nibbler.unconsumedBytes)
+	if nextRune == utf8.RuneError && sizeOfRune == 1 {
+		invalidByte := nibbler.unconsumedBytes[0]
+
+		switch nibbler.invalidUTF8Policy {
+		case ReplaceInvalidUTF8:
+			nextRune = utf8.RuneError
+		case PassInvalidUTF8Through:
+			nextRune = rawByteRune(invalidByte)
+		default:
+			return utf8.RuneError, &InvalidUTF8Error{ByteOffset: nibbler.positionOfNextRune.byteOffset, Byte: invalidByte}
+		}
+	}
+
+	nibbler.unconsumedBytes = nibbler.unconsumedBytes[sizeOfRune:]
+	nibbler.positionOfLastReadRune = nibbler.positionOfNextRune
+	nibbler.positionOfNextRune.byteOffset += int64(sizeOfRune)
+	nibbler.positionOfNextRune.runeOffset++
+	if nextRune == '\n' {
+		nibbler.positionOfNextRune.line++
+	}
+	nibbler.lastReadRune = nextRune
+	nibbler.lastReadRuneCanBeUnread = true
+
+	return nextRune, nil
+}
+
+// UnreadCharacter puts back the most recently read character. Only one character can be unread.
+func (nibbler *utf8ReaderNibbler) UnreadCharacter() error {
+	if !nibbler.lastReadRuneCanBeUnread || nibbler.lastReadRuneWasUnread {
+		return errors.New("no character to unread")
+	}
+
+	nibbler.lastReadRuneWasUnread = true
+	return nil
+}
+
+// PeekAtNextCharacter returns the next character without reading it.
+func (nibbler *utf8ReaderNibbler) PeekAtNextCharacter() (rune, error) {
+	nextRune, err := nibbler.ReadCharacter()
+	if err != nil {
+		return utf8.RuneError, err
+	}
+
+	return nextRune, nibbler.UnreadCharacter()
+}
+
+// positionOfNextCharacter returns the position of the next character that will be read. Lines are counted by
+// the line feeds (U+000A) that have been read.
+func (nibbler *utf8ReaderNibbler) positionOfNextCharacter() inputPosition {
+	if nibbler.lastReadRuneWasUnread {
+		return nibbler.positionOfLastReadRune
+	}
+
+	return nibbler.positionOfNextRune
+}
+
+// readFromTheSourceUntilTheNextRuneIsComplete reads from the source until unconsumedBytes starts with a complete
+// UTF-8 sequence (or an invalid byte), or the source has no more bytes. Returns the error from the source
+// (including io.EOF) if there are no unconsumed bytes, or if the source failed in the middle of a sequence.
+func (nibbler *utf8ReaderNibbler) readFromTheSourceUntilTheNextRuneIsComplete() error {
+	for !utf8.FullRune(nibbler.unconsumedBytes) && nibbler.sourceError == nil {
+		if len(nibbler.unconsumedBytes) == 0 || cap(nibbler.unconsumedBytes)-len(nibbler.unconsumedBytes) < utf8.UTFMax {
+			nibbler.unconsumedBytes = nibbler.readBuffer[:copy(nibbler.readBuffer, nibbler.unconsumedBytes)]
+		}
+
+		endOfUnconsumedBytes := len(nibbler.unconsumedBytes)
+		bytesRead, err := nibbler.source.Read(nibbler.unconsumedBytes[endOfUnconsumedBytes:cap(nibbler.unconsumedBytes)])
+		nibbler.unconsumedBytes = nibbler.unconsumedBytes[:endOfUnconsumedBytes+bytesRead]
+		nibbler.sourceError = err
+	}
+
+	if len(nibbler.unconsumedBytes) == 0 {
+		return nibbler.sourceError
+	}
+
+	// a sequence that is cut short by an error (rather than by the end of the input) is not reported as invalid
+	if !utf8.FullRune(nibbler.unconsumedBytes) && nibbler.sourceError != io.EOF {
+		return nibbler.sourceError
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package text
+
+import (
+	"io"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// InputNormalization is the Unicode normalization form to which a Wrapper converts the text it wraps.
+type InputNormalization int
+
+const (
+	// NoNormalization wraps text as it is provided. This is the default.
+	NoNormalization InputNormalization = iota
+
+	// NormalizeToNFC converts text to Normalization Form C (canonical composition), so that, for example, "e"
+	// followed by a combining acute accent becomes the single rune "é".
+	NormalizeToNFC
+
+	// NormalizeToNFD converts text to Normalization Form D (canonical decomposition), which is how file names are
+	// stored on macOS filesystems.
+	NormalizeToNFD
+
+	// NormalizeToNFKC converts text to Normalization Form KC (compatibility composition), which also replaces
+	// compatibility characters, such as ligatures and fullwidth forms, with their ordinary equivalents.
+	NormalizeToNFKC
+
+	// NormalizeToNFKD converts text to Normalization Form KD (compatibility decomposition).
+	NormalizeToNFKD
+)
+
+// ChangeInputNormalizationTo sets the Unicode normalization form to which text is converted before it is wrapped.
+// Text is converted as it is read, so a reader is not read in full before wrapping begins. The indent strings are
+// not converted. The default is NoNormalization.
+func (wrapper *Wrapper) ChangeInputNormalizationTo(normalization InputNormalization) *Wrapper {
+	wrapper.inputNormalization = normalization
+	return wrapper
+}
+
+// UsingInputNormalization is the same as ChangeInputNormalizationTo(), but provides a more readable name if this
+// is chained with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingInputNormalization(text.NormalizeToNFC).UsingRowWidth(40)
+func (wrapper *Wrapper) UsingInputNormalization(normalization InputNormalization) *Wrapper {
+	return wrapper.ChangeInputNormalizationTo(normalization)
+}
+
+// normalizingReaderFor returns a reader that converts the text read from reader to the Wrapper's input
+// normalization form, or reader itself if there is none.
+func (wrapper *Wrapper) normalizingReaderFor(reader io.Reader) io.Reader {
+	switch wrapper.inputNormalization {
+	case NormalizeToNFC:
+		return norm.NFC.Reader(reader)
+	case NormalizeToNFD:
+		return norm.NFD.Reader(reader)
+	case NormalizeToNFKC:
+		return norm.NFKC.Reader(reader)
+	case NormalizeToNFKD:
+		return norm.NFKD.Reader(reader)
+	default:
+		return reader
+	}
+}
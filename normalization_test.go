@@ -0,0 +1,101 @@
+package text_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type NormalizationTestCase struct {
+	testName              string
+	normalization         text.InputNormalization
+	rowWidth              uint
+	unwrappedString       string
+	expectedWrappedString string
+}
+
+func (testCase *NormalizationTestCase) RunTest() error {
+	wrapper := text.NewWrapper().UsingRowWidth(testCase.rowWidth).UsingInputNormalization(testCase.normalization)
+
+	wrappedString, err := wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	wrappedString, err = wrapper.WrapUTF8TextFromAReader(strings.NewReader(testCase.unwrappedString))
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error from reader: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] from reader expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestWrapUsingInputNormalization(t *testing.T) {
+	testCases := []*NormalizationTestCase{
+		{
+			testName:              "NFD text without normalization",
+			normalization:         text.NoNormalization,
+			rowWidth:              9,
+			unwrappedString:       "café café",
+			expectedWrappedString: "café\ncafé",
+		},
+		{
+			testName:              "NFD text normalized to NFC",
+			normalization:         text.NormalizeToNFC,
+			rowWidth:              9,
+			unwrappedString:       "café café",
+			expectedWrappedString: "café café",
+		},
+		{
+			testName:              "NFC text is unchanged by NFC normalization",
+			normalization:         text.NormalizeToNFC,
+			rowWidth:              9,
+			unwrappedString:       "café café",
+			expectedWrappedString: "café café",
+		},
+		{
+			testName:              "NFC text normalized to NFD",
+			normalization:         text.NormalizeToNFD,
+			rowWidth:              9,
+			unwrappedString:       "café café",
+			expectedWrappedString: "café\ncafé",
+		},
+		{
+			testName:              "compatibility characters normalized to NFKC",
+			normalization:         text.NormalizeToNFKC,
+			rowWidth:              10,
+			unwrappedString:       "ﬁne ＡＢＣ café",
+			expectedWrappedString: "fine ABC\ncafé",
+		},
+		{
+			testName:              "compatibility characters normalized to NFKD",
+			normalization:         text.NormalizeToNFKD,
+			rowWidth:              10,
+			unwrappedString:       "ﬁne ＡＢＣ café",
+			expectedWrappedString: "fine ABC\ncafé",
+		},
+		{
+			testName:              "normalization across a long input",
+			normalization:         text.NormalizeToNFC,
+			rowWidth:              79,
+			unwrappedString:       strings.Repeat("café ", 2000),
+			expectedWrappedString: strings.TrimSuffix(strings.Repeat(strings.TrimSuffix(strings.Repeat("café ", 16), " ")+"\n", 125), "\n"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrRowWidthTooSmall is returned by NewWrapperWithOptions() if the row width is not larger than the width of
+	// each indent string.
+	ErrRowWidthTooSmall = errors.New("row width must be larger than the row indent strings")
+
+	// ErrMeasurerIsNil is returned by NewWrapperWithOptions() if the Measurer is nil.
+	ErrMeasurerIsNil = errors.New("measurer must not be nil")
+
+	// ErrInvalidOptionValue is returned by NewWrapperWithOptions() if an option is set to a value that is not one
+	// of the constants defined for it (for example, a BidiMode other than BidiDisabled, BidiLogicalOrder or
+	// BidiVisualOrder).
+	ErrInvalidOptionValue = errors.New("invalid option value")
+)
+
+// Option sets one part of the configuration of a Wrapper created by NewWrapperWithOptions(). There is an Option
+// for each of the Wrapper's ChangeXTo() methods.
+type Option func(configuration *wrapperConfiguration)
+
+// wrapperConfiguration collects the Options passed to NewWrapperWithOptions(). The row width is kept separately
+// from the Wrapper, because its units depend on whether the Wrapper has FontMetrics, which may be set by a later
+// Option.
+type wrapperConfiguration struct {
+	wrapper                *Wrapper
+	rowWidth               float64
+	rowWidthIsFromTerminal bool
+}
+
+// NewWrapperWithOptions creates a Wrapper with the same defaults as NewWrapper(), then applies options. The
+// complete configuration is validated after all of the options are applied, so (unlike the ChangeXTo() methods,
+// which panic) the order of the options does not matter. If an option is provided more than once, the last one is
+// used. If the configuration is not valid, the returned error wraps one of ErrRowWidthTooSmall, ErrMeasurerIsNil
+// or ErrInvalidOptionValue, and can be tested using errors.Is(), as in:
+//    wrapper, err := text.NewWrapperWithOptions(text.WithRowWidth(40), text.WithIndentStringForRowsAfterTheFirst("  "))
+//    if errors.Is(err, text.ErrRowWidthTooSmall) {
+//        ...
+//    }
+func NewWrapperWithOptions(options ...Option) (*Wrapper, error) {
+	configuration := &wrapperConfiguration{
+		wrapper:                NewWrapper(),
+		rowWidth:               79,
+		rowWidthIsFromTerminal: false,
+	}
+
+	for _, option := range options {
+		option(configuration)
+	}
+
+	if err := configuration.validate(); err != nil {
+		return nil, err
+	}
+
+	return configuration.wrapper, nil
+}
+
+func (configuration *wrapperConfiguration) validate() error {
+	wrapper := configuration.wrapper
+
+	if wrapper.measurer == nil {
+		return ErrMeasurerIsNil
+	}
+
+	for _, setting := range []struct {
+		name         string
+		value        int
+		largestValue int
+	}{
+		{"truncation position", int(wrapper.truncationPosition), int(TruncateMiddle)},
+		{"bidi mode", int(wrapper.bidiMode), int(BidiVisualOrder)},
+		{"paragraph direction", int(wrapper.paragraphDirection), int(RightToLeftParagraph)},
+		{"right-to-left alignment", int(wrapper.rightToLeftAlignment), int(AlignCenter)},
+		{"kinsoku adjustment", int(wrapper.kinsokuAdjustment), int(KinsokuPushIn)},
+		{"input normalization", int(wrapper.inputNormalization), int(NormalizeToNFKD)},
+		{"invalid UTF-8 policy", int(wrapper.invalidUTF8Policy), int(PassInvalidUTF8Through)},
+	} {
+		if setting.value < 0 || setting.value > setting.largestValue {
+			return fmt.Errorf("%w: %s (%d)", ErrInvalidOptionValue, setting.name, setting.value)
+		}
+	}
+
+	rowWidth := configuration.rowWidth
+	if configuration.rowWidthIsFromTerminal {
+		terminalColumns, _ := TerminalColumns()
+		rowWidth = float64(wrapper.rowWidthForTerminalColumns(terminalColumns))
+	}
+
+	return wrapper.changeRowWidthWithFontMetricsTo(wrapper.fontMetrics, rowWidth)
+}
+
+// WithRowWidth is the Option for ChangeRowWidthTo().
+func WithRowWidth(numberOfColumns uint) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.rowWidth = float64(numberOfColumns)
+		configuration.rowWidthIsFromTerminal = false
+	}
+}
+
+// WithTerminalRowWidth is the Option for ChangeRowWidthToTerminalWidth().
+func WithTerminalRowWidth() Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.rowWidthIsFromTerminal = true
+	}
+}
+
+// WithFontMetrics is the Option for ChangeFontMetricsTo().
+func WithFontMetrics(metrics FontMetrics, rowWidth float64) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.fontMetrics = metrics
+		configuration.rowWidth = rowWidth
+		configuration.rowWidthIsFromTerminal = false
+	}
+}
+
+// WithIndentStringForFirstRow is the Option for ChangeIndentStringForFirstRowTo().
+func WithIndentStringForFirstRow(indent string) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.initialLineIndentString = []rune(indent)
+	}
+}
+
+// WithIndentStringForRowsAfterTheFirst is the Option for ChangeIndentStringForRowsAfterTheFirstTo().
+func WithIndentStringForRowsAfterTheFirst(indent string) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.subsequentLinesIndentString = []rune(indent)
+	}
+}
+
+// WithIndentDetectionFromInput is the Option for ChangeIndentDetectionFromInputTo().
+func WithIndentDetectionFromInput(detectionIsEnabled bool) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.detectIndentsFromInput = detectionIsEnabled
+	}
+}
+
+// WithMaximumRows is the Option for ChangeMaximumRowsTo().
+func WithMaximumRows(numberOfRows uint) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.maximumRows = numberOfRows
+	}
+}
+
+// WithEllipsisString is the Option for ChangeEllipsisStringTo().
+func WithEllipsisString(ellipsis string) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.ellipsisString = []rune(ellipsis)
+	}
+}
+
+// WithTruncationPosition is the Option for ChangeTruncationPositionTo().
+func WithTruncationPosition(position TruncationPosition) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.truncationPosition = position
+	}
+}
+
+// WithMeasurer is the Option for ChangeMeasurerTo().
+func WithMeasurer(measurer Measurer) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.measurer = measurer
+	}
+}
+
+// WithBidiMode is the Option for ChangeBidiModeTo().
+func WithBidiMode(mode BidiMode) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.bidiMode = mode
+	}
+}
+
+// WithParagraphDirection is the Option for ChangeParagraphDirectionTo().
+func WithParagraphDirection(direction ParagraphDirection) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.paragraphDirection = direction
+	}
+}
+
+// WithRightToLeftAlignment is the Option for ChangeRightToLeftAlignmentTo().
+func WithRightToLeftAlignment(alignment Alignment) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.rightToLeftAlignment = alignment
+	}
+}
+
+// WithWordSegmenter is the Option for ChangeWordSegmenterTo().
+func WithWordSegmenter(segmenter WordSegmenter) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.wordSegmenter = segmenter
+	}
+}
+
+// WithKinsokuAdjustment is the Option for ChangeKinsokuAdjustmentTo().
+func WithKinsokuAdjustment(adjustment KinsokuAdjustment) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.kinsokuAdjustment = adjustment
+	}
+}
+
+// WithKinsokuProhibitedCharacters is the Option for ChangeKinsokuProhibitedCharactersTo().
+func WithKinsokuProhibitedCharacters(prohibitedAtStartOfRow string, prohibitedAtEndOfRow string) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.ChangeKinsokuProhibitedCharactersTo(prohibitedAtStartOfRow, prohibitedAtEndOfRow)
+	}
+}
+
+// WithInputNormalization is the Option for ChangeInputNormalizationTo().
+func WithInputNormalization(normalization InputNormalization) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.inputNormalization = normalization
+	}
+}
+
+// WithByteOrderMarkDetection is the Option for ChangeByteOrderMarkDetectionTo().
+func WithByteOrderMarkDetection(detectByteOrderMark bool) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.detectByteOrderMark = detectByteOrderMark
+	}
+}
+
+// WithInvalidUTF8Policy is the Option for ChangeInvalidUTF8PolicyTo().
+func WithInvalidUTF8Policy(policy InvalidUTF8Policy) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.invalidUTF8Policy = policy
+	}
+}
+
+// WithInputByteLimit is the Option for ChangeInputByteLimitTo().
+func WithInputByteLimit(numberOfBytes uint) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.inputByteLimit = numberOfBytes
+	}
+}
+
+// WithOutputRowLimit is the Option for ChangeOutputRowLimitTo().
+func WithOutputRowLimit(numberOfRows uint) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.outputRowLimit = numberOfRows
+	}
+}
+
+// WithWordLengthLimit is the Option for ChangeWordLengthLimitTo().
+func WithWordLengthLimit(numberOfRunes uint) Option {
+	return func(configuration *wrapperConfiguration) {
+		configuration.wrapper.wordLengthLimit = numberOfRunes
+	}
+}
@@ -0,0 +1,140 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type WrapperOptionsTestCase struct {
+	testName              string
+	options               []text.Option
+	unwrappedString       string
+	expectedWrappedString string
+	expectedError         error
+}
+
+func (testCase *WrapperOptionsTestCase) RunTest() error {
+	wrapper, err := text.NewWrapperWithOptions(testCase.options...)
+
+	if testCase.expectedError != nil {
+		if !errors.Is(err, testCase.expectedError) {
+			return fmt.Errorf("[%s] expected error (%v), got = (%v)", testCase.testName, testCase.expectedError, err)
+		}
+
+		if wrapper != nil {
+			return fmt.Errorf("[%s] expected no Wrapper with the error, got one", testCase.testName)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	wrappedString, err := wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestNewWrapperWithOptions(t *testing.T) {
+	testCases := []*WrapperOptionsTestCase{
+		{
+			testName:              "no options",
+			options:               nil,
+			unwrappedString:       "hello world",
+			expectedWrappedString: "hello world",
+		},
+		{
+			testName:              "row width and indents",
+			options:               []text.Option{text.WithRowWidth(12), text.WithIndentStringForFirstRow("* "), text.WithIndentStringForRowsAfterTheFirst("  ")},
+			unwrappedString:       "the quick brown fox jumps",
+			expectedWrappedString: "* the quick\n  brown fox\n  jumps",
+		},
+		{
+			testName:              "indent wider than the default row width is valid when the row width is set after it",
+			options:               []text.Option{text.WithIndentStringForRowsAfterTheFirst(fmt.Sprintf("%90s", "")), text.WithRowWidth(100)},
+			unwrappedString:       "hello",
+			expectedWrappedString: "hello",
+		},
+		{
+			testName:      "row width smaller than an indent set after it",
+			options:       []text.Option{text.WithRowWidth(4), text.WithIndentStringForFirstRow("    ")},
+			expectedError: text.ErrRowWidthTooSmall,
+		},
+		{
+			testName:      "row width smaller than an indent set before it",
+			options:       []text.Option{text.WithIndentStringForRowsAfterTheFirst("     "), text.WithRowWidth(5)},
+			expectedError: text.ErrRowWidthTooSmall,
+		},
+		{
+			testName:      "indent measured with the measurer",
+			options:       []text.Option{text.WithRowWidth(4), text.WithIndentStringForFirstRow("日本"), text.WithMeasurer(text.EastAsianWideMeasurer)},
+			expectedError: text.ErrRowWidthTooSmall,
+		},
+		{
+			testName:      "zero row width",
+			options:       []text.Option{text.WithRowWidth(0)},
+			expectedError: text.ErrRowWidthTooSmall,
+		},
+		{
+			testName:              "font metrics row width is in points",
+			options:               []text.Option{text.WithIndentStringForFirstRow("  "), text.WithFontMetrics(text.NewFontMetricsTable(10), 50)},
+			unwrappedString:       "aaa bbb",
+			expectedWrappedString: "  aaa\nbbb",
+		},
+		{
+			testName:      "font metrics row width too small for the indent",
+			options:       []text.Option{text.WithFontMetrics(text.NewFontMetricsTable(10), 19.5), text.WithIndentStringForFirstRow("  ")},
+			expectedError: text.ErrRowWidthTooSmall,
+		},
+		{
+			testName:      "nil measurer",
+			options:       []text.Option{text.WithMeasurer(nil)},
+			expectedError: text.ErrMeasurerIsNil,
+		},
+		{
+			testName:      "invalid bidi mode",
+			options:       []text.Option{text.WithBidiMode(text.BidiMode(7))},
+			expectedError: text.ErrInvalidOptionValue,
+		},
+		{
+			testName:      "invalid truncation position",
+			options:       []text.Option{text.WithTruncationPosition(text.TruncationPosition(-1))},
+			expectedError: text.ErrInvalidOptionValue,
+		},
+		{
+			testName:              "the last of repeated options is used",
+			options:               []text.Option{text.WithRowWidth(5), text.WithMaximumRows(1), text.WithRowWidth(11), text.WithEllipsisString("...")},
+			unwrappedString:       "hello there world",
+			expectedWrappedString: "hello...",
+		},
+		{
+			testName: "settings other than width",
+			options: []text.Option{
+				text.WithRowWidth(5),
+				text.WithKinsokuAdjustment(text.KinsokuPushIn),
+				text.WithInvalidUTF8Policy(text.ReplaceInvalidUTF8),
+				text.WithInputNormalization(text.NormalizeToNFC),
+			},
+			unwrappedString:       "あいうえお。\xffかきé",
+			expectedWrappedString: "あいうえお。\n�かきé",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// WrapParagraphsFromAReader reads UTF-8 text from reader, wraps each paragraph in it separately and writes the
+// wrapped paragraphs to writer. Paragraphs are separated by one or more blank lines (lines that are empty or have
+// only whitespace). Each paragraph is wrapped as by AppendWrapped(), so the indents, the maximum rows, indent
+// detection and the limits apply to each paragraph on its own. Each wrapped paragraph is followed by a line break,
+// and there is a blank line between wrapped paragraphs.
+//
+// If wrapping a paragraph fails, the text wrapped from that paragraph before the error (see WrapError) is written,
+// and the error is returned. The position in a WrapError is adjusted so that it is from the start of the input
+// read from reader, and the row is counted from the first row written to writer.
+//
+// If there is an input byte limit, no more of reader is read once a paragraph is longer than the limit, and wrapping
+// stops at that paragraph with ErrLimitExceeded.
+func (wrapper *Wrapper) WrapParagraphsFromAReader(reader io.Reader, writer io.Writer) error {
+	paragraphs := newParagraphReader(reader, wrapper.inputByteLimit)
+	wrappedParagraphs := newWrappedParagraphWriter(writer, wrapper.lineBreakSequence)
+	var bufferOfWrappedText []byte
+
+	for {
+		nextParagraph, err := paragraphs.readParagraph()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		bufferOfWrappedText, err = wrapper.AppendWrapped(bufferOfWrappedText[:0], nextParagraph.text)
+		if err := wrappedParagraphs.write(bufferOfWrappedText, err, nextParagraph); err != nil {
+			return err
+		}
+	}
+}
+
+// WrapParagraphsFromAReaderConcurrently is the same as WrapParagraphsFromAReader(), but wraps paragraphs
+// concurrently, using numberOfWorkers goroutines. If numberOfWorkers is 0 or less, runtime.GOMAXPROCS(0) goroutines
+// are used. The paragraphs are written to writer in the order they are read from reader, and writer receives exactly
+// the same bytes (and the same error, if one occurs) as it would from WrapParagraphsFromAReader(). Only the calling
+// goroutine writes to writer. The goroutines share the Wrapper, so it must not be changed until this returns. This
+// does not return until every goroutine it started has stopped, so reader is not used after it returns.
+func (wrapper *Wrapper) WrapParagraphsFromAReaderConcurrently(reader io.Reader, writer io.Writer, numberOfWorkers int) error {
+	if numberOfWorkers <= 0 {
+		numberOfWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	paragraphsToWrap := make(chan *paragraphToWrap, numberOfWorkers)
+	paragraphsInOrder := make(chan *paragraphToWrap, 2*numberOfWorkers)
+	writingHasStopped := make(chan struct{})
+
+	var goroutinesThatHaveNotStopped sync.WaitGroup
+	goroutinesThatHaveNotStopped.Add(1 + numberOfWorkers)
+
+	go func() {
+		defer goroutinesThatHaveNotStopped.Done()
+		defer close(paragraphsInOrder)
+		defer close(paragraphsToWrap)
+
+		paragraphs := newParagraphReader(reader, wrapper.inputByteLimit)
+		for {
+			nextParagraph, err := paragraphs.readParagraph()
+			if err != nil {
+				if err != io.EOF {
+					// the read error is delivered in order, after the paragraphs that were read before it
+					paragraphWithReadError := &paragraphToWrap{result: make(chan wrappedParagraph, 1)}
+					paragraphWithReadError.result <- wrappedParagraph{err: err}
+
+					select {
+					case paragraphsInOrder <- paragraphWithReadError:
+					case <-writingHasStopped:
+					}
+				}
+
+				return
+			}
+
+			nextParagraphToWrap := &paragraphToWrap{paragraph: nextParagraph, result: make(chan wrappedParagraph, 1)}
+
+			select {
+			case paragraphsInOrder <- nextParagraphToWrap:
+			case <-writingHasStopped:
+				return
+			}
+
+			select {
+			case paragraphsToWrap <- nextParagraphToWrap:
+			case <-writingHasStopped:
+				return
+			}
+		}
+	}()
+
+	for workerNumber := 0; workerNumber < numberOfWorkers; workerNumber++ {
+		go func() {
+			defer goroutinesThatHaveNotStopped.Done()
+
+			for nextParagraphToWrap := range paragraphsToWrap {
+				wrappedText, err := wrapper.AppendWrapped(nil, nextParagraphToWrap.paragraph.text)
+				nextParagraphToWrap.result <- wrappedParagraph{text: wrappedText, err: err}
+			}
+		}()
+	}
+
+	defer goroutinesThatHaveNotStopped.Wait()
+	defer close(writingHasStopped)
+
+	wrappedParagraphs := newWrappedParagraphWriter(writer, wrapper.lineBreakSequence)
+
+	for nextParagraphToWrap := range paragraphsInOrder {
+		result := <-nextParagraphToWrap.result
+		if nextParagraphToWrap.paragraph == nil {
+			return result.err
+		}
+
+		if err := wrappedParagraphs.write(result.text, result.err, nextParagraphToWrap.paragraph); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paragraphToWrap is a paragraph that is sent to the worker goroutines of WrapParagraphsFromAReaderConcurrently().
+// The worker that wraps it sends the result on result, which is buffered, so that the worker does not wait for the
+// wrapped paragraphs before it to be written. If paragraph is nil, result has the error that stopped reading.
+type paragraphToWrap struct {
+	paragraph *paragraph
+	result    chan wrappedParagraph
+}
+
+type wrappedParagraph struct {
+	text []byte
+	err  error
+}
+
+// paragraph is the text of a paragraph and the position of its first character in the input.
+type paragraph struct {
+	text     []byte
+	position inputPosition
+}
+
+// paragraphReader reads paragraphs from UTF-8 text. The text of each paragraph is its lines, including their line
+// breaks. The blank lines between paragraphs are not part of any paragraph. If paragraphByteLimit is more than 0,
+// a paragraph is read only until it is longer than paragraphByteLimit, and nothing after it is read.
+type paragraphReader struct {
+	lineReader         *bufio.Reader
+	paragraphByteLimit uint
+	positionOfNextLine inputPosition
+	atTheEndOfTheInput bool
+}
+
+func newParagraphReader(reader io.Reader, paragraphByteLimit uint) *paragraphReader {
+	return &paragraphReader{
+		lineReader:         bufio.NewReader(reader),
+		paragraphByteLimit: paragraphByteLimit,
+		positionOfNextLine: inputPosition{byteOffset: 0, runeOffset: 0, line: 1},
+		atTheEndOfTheInput: false,
+	}
+}
+
+// readParagraph returns the next paragraph, or io.EOF if there are no more. If reading fails, the paragraph being
+// read is dropped and the error is returned. A paragraph that is longer than the paragraph byte limit is returned
+// as soon as that is known, with no more than a line reader buffer of bytes after the limit, so that wrapping it
+// fails with ErrLimitExceeded.
+func (reader *paragraphReader) readParagraph() (*paragraph, error) {
+	var nextParagraph *paragraph
+
+	for !reader.atTheEndOfTheInput {
+		lengthOfParagraph := 0
+		if nextParagraph != nil {
+			lengthOfParagraph = len(nextParagraph.text)
+		}
+
+		line, err := reader.readLineOrPartOfALine(lengthOfParagraph)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		reader.atTheEndOfTheInput = err == io.EOF
+
+		positionOfLine := reader.positionOfNextLine
+		reader.positionOfNextLine.byteOffset += int64(len(line))
+		reader.positionOfNextLine.runeOffset += int64(utf8.RuneCount(line))
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			reader.positionOfNextLine.line++
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			if nextParagraph != nil {
+				return nextParagraph, nil
+			}
+
+			continue
+		}
+
+		if nextParagraph == nil {
+			nextParagraph = &paragraph{text: line, position: positionOfLine}
+		} else {
+			nextParagraph.text = append(nextParagraph.text, line...)
+		}
+
+		if reader.paragraphIsOverTheLimit(len(nextParagraph.text)) {
+			reader.atTheEndOfTheInput = true
+		}
+	}
+
+	if nextParagraph != nil {
+		return nextParagraph, nil
+	}
+
+	return nil, io.EOF
+}
+
+// readLineOrPartOfALine reads the next line, including its line break. If the line would make a paragraph that is
+// lengthOfParagraph bytes long longer than the paragraph byte limit, only the part of the line that is read before
+// that is known is returned.
+func (reader *paragraphReader) readLineOrPartOfALine(lengthOfParagraph int) ([]byte, error) {
+	var line []byte
+
+	for {
+		partOfTheLine, err := reader.lineReader.ReadSlice('\n')
+		line = append(line, partOfTheLine...)
+
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+
+		if reader.paragraphIsOverTheLimit(lengthOfParagraph + len(line)) {
+			return line, nil
+		}
+	}
+}
+
+func (reader *paragraphReader) paragraphIsOverTheLimit(lengthOfParagraph int) bool {
+	return reader.paragraphByteLimit > 0 && uint(lengthOfParagraph) > reader.paragraphByteLimit
+}
+
+// wrappedParagraphWriter writes wrapped paragraphs, separated by blank lines, and counts the rows it has written.
+type wrappedParagraphWriter struct {
+	writer                    io.Writer
+	lineBreakSequence         []byte
+	numberOfParagraphsWritten int
+	numberOfRowsWritten       int
+}
+
+func newWrappedParagraphWriter(writer io.Writer, lineBreakSequence string) *wrappedParagraphWriter {
+	return &wrappedParagraphWriter{
+		writer:                    writer,
+		lineBreakSequence:         []byte(lineBreakSequence),
+		numberOfParagraphsWritten: 0,
+		numberOfRowsWritten:       0,
+	}
+}
+
+// write writes the text wrapped from sourceParagraph. If wrapping failed with errorFromWrapping, the wrapped text
+// is written without a line break after it, and errorFromWrapping is returned, with its position adjusted to the
+// position of the paragraph in the input.
+func (paragraphWriter *wrappedParagraphWriter) write(wrappedText []byte, errorFromWrapping error, sourceParagraph *paragraph) error {
+	if len(wrappedText) > 0 {
+		if paragraphWriter.numberOfParagraphsWritten > 0 {
+			if _, err := paragraphWriter.writer.Write(paragraphWriter.lineBreakSequence); err != nil {
+				return err
+			}
+
+			paragraphWriter.numberOfRowsWritten++
+		}
+
+		if _, err := paragraphWriter.writer.Write(wrappedText); err != nil {
+			return err
+		}
+	}
+
+	if errorFromWrapping != nil {
+		return paragraphWriter.errorAtPositionInTheInput(errorFromWrapping, sourceParagraph)
+	}
+
+	if len(wrappedText) > 0 {
+		if _, err := paragraphWriter.writer.Write(paragraphWriter.lineBreakSequence); err != nil {
+			return err
+		}
+
+		paragraphWriter.numberOfParagraphsWritten++
+		paragraphWriter.numberOfRowsWritten += bytes.Count(wrappedText, paragraphWriter.lineBreakSequence) + 1
+	}
+
+	return nil
+}
+
+func (paragraphWriter *wrappedParagraphWriter) errorAtPositionInTheInput(err error, sourceParagraph *paragraph) error {
+	var wrapError *WrapError
+	if !errors.As(err, &wrapError) {
+		return err
+	}
+
+	return &WrapError{
+		Err:        wrapError.Err,
+		ByteOffset: sourceParagraph.position.byteOffset + wrapError.ByteOffset,
+		RuneOffset: sourceParagraph.position.runeOffset + wrapError.RuneOffset,
+		Line:       sourceParagraph.position.line + wrapError.Line - 1,
+		Row:        paragraphWriter.numberOfRowsWritten + wrapError.Row,
+	}
+}
@@ -0,0 +1,310 @@
+package text_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/blorticus-go/text"
+)
+
+type ParagraphsTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	unwrappedString       string
+	expectedWrappedString string
+	expectedWrapError     *text.WrapError
+}
+
+func (testCase *ParagraphsTestCase) RunTest() error {
+	var sequentialOutput bytes.Buffer
+	sequentialErr := testCase.wrapper.WrapParagraphsFromAReader(strings.NewReader(testCase.unwrappedString), &sequentialOutput)
+
+	if err := errorUnlessTheExpectedWrapError(testCase.expectedWrapError, sequentialErr); err != nil {
+		return fmt.Errorf("[%s] %s", testCase.testName, err)
+	}
+
+	if sequentialOutput.String() != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, sequentialOutput.String())
+	}
+
+	for _, numberOfWorkers := range []int{0, 1, 2, 8} {
+		var concurrentOutput bytes.Buffer
+		concurrentErr := testCase.wrapper.WrapParagraphsFromAReaderConcurrently(strings.NewReader(testCase.unwrappedString), &concurrentOutput, numberOfWorkers)
+
+		if err := errorUnlessTheExpectedWrapError(testCase.expectedWrapError, concurrentErr); err != nil {
+			return fmt.Errorf("[%s] (workers = %d) %s", testCase.testName, numberOfWorkers, err)
+		}
+
+		if concurrentOutput.String() != sequentialOutput.String() {
+			return fmt.Errorf("[%s] (workers = %d) expected = (%q), got = (%q)", testCase.testName, numberOfWorkers, sequentialOutput.String(), concurrentOutput.String())
+		}
+	}
+
+	return nil
+}
+
+func errorUnlessTheExpectedWrapError(expectedWrapError *text.WrapError, err error) error {
+	if expectedWrapError == nil {
+		if err != nil {
+			return fmt.Errorf("unexpected error: %s", err)
+		}
+
+		return nil
+	}
+
+	var wrapError *text.WrapError
+	if !errors.As(err, &wrapError) {
+		return fmt.Errorf("expected a WrapError, got = (%v)", err)
+	}
+
+	if !errors.Is(wrapError, expectedWrapError.Err) || wrapError.ByteOffset != expectedWrapError.ByteOffset || wrapError.RuneOffset != expectedWrapError.RuneOffset ||
+		wrapError.Line != expectedWrapError.Line || wrapError.Row != expectedWrapError.Row {
+		return fmt.Errorf("expected error = (%v), got = (%v)", expectedWrapError, wrapError)
+	}
+
+	return nil
+}
+
+func TestWrapParagraphs(t *testing.T) {
+	testCases := []*ParagraphsTestCase{
+		{
+			testName:              "empty input",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			unwrappedString:       "",
+			expectedWrappedString: "",
+		},
+		{
+			testName:              "only blank lines",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			unwrappedString:       "\n  \n\t\r\n",
+			expectedWrappedString: "",
+		},
+		{
+			testName:              "one paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			unwrappedString:       "one two three\nfour five",
+			expectedWrappedString: "one two\nthree four\nfive\n",
+		},
+		{
+			testName:              "paragraphs separated by blank lines",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			unwrappedString:       "\n\none two three\n\n\n \t \nfour\nfive six\r\n\r\nseven\n\n",
+			expectedWrappedString: "one two\nthree\n\nfour five\nsix\n\nseven\n",
+		},
+		{
+			testName:              "indents apply to each paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingIndentStringForFirstRow("* ").UsingIndentStringForRowsAfterTheFirst("  "),
+			unwrappedString:       "one two three\n\nfour five",
+			expectedWrappedString: "* one two\n  three\n\n* four\n  five\n",
+		},
+		{
+			testName:              "maximum rows apply to each paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingMaximumRows(1),
+			unwrappedString:       "one two three\n\nfour five six",
+			expectedWrappedString: "one two…\n\nfour five…\n",
+		},
+		{
+			testName:              "error in a later paragraph",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingOutputRowLimit(2),
+			unwrappedString:       "one two three\n\nfour\n\nfive six\nseven eight nine",
+			expectedWrappedString: "one two\nthree\n\nfour\n\nfive six\nseven",
+			expectedWrapError: &text.WrapError{
+				Err:        text.ErrLimitExceeded,
+				ByteOffset: 35,
+				RuneOffset: 35,
+				Line:       6,
+				Row:        7,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestWrapParagraphsOfGeneratedText(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"a", "to", "the", "quick", "brown", "jumped", "über", "日本語", "wrapping", "incomprehensibilities"}
+	separators := []string{" ", " ", " ", "\n", "  ", "\t", "\n\n", "\n \n\n", "\r\n\r\n"}
+
+	var textBuilder strings.Builder
+	for numberOfWords := 5000; numberOfWords > 0; numberOfWords-- {
+		textBuilder.WriteString(words[random.Intn(len(words))])
+		textBuilder.WriteString(separators[random.Intn(len(separators))])
+	}
+
+	for _, wrapper := range []*text.Wrapper{
+		text.NewWrapper().UsingRowWidth(12),
+		text.NewWrapper().UsingRowWidth(30).UsingIndentStringForRowsAfterTheFirst("    "),
+		text.NewWrapper().UsingRowWidth(20).UsingOutputRowLimit(3),
+	} {
+		var sequentialOutput bytes.Buffer
+		sequentialErr := wrapper.WrapParagraphsFromAReader(strings.NewReader(textBuilder.String()), &sequentialOutput)
+
+		for _, numberOfWorkers := range []int{0, 1, 3, 16} {
+			var concurrentOutput bytes.Buffer
+			concurrentErr := wrapper.WrapParagraphsFromAReaderConcurrently(strings.NewReader(textBuilder.String()), &concurrentOutput, numberOfWorkers)
+
+			if !bytes.Equal(concurrentOutput.Bytes(), sequentialOutput.Bytes()) {
+				t.Errorf("(workers = %d) expected the same output as WrapParagraphsFromAReader()", numberOfWorkers)
+			}
+
+			if fmt.Sprint(concurrentErr) != fmt.Sprint(sequentialErr) {
+				t.Errorf("(workers = %d) expected error = (%v), got = (%v)", numberOfWorkers, sequentialErr, concurrentErr)
+			}
+		}
+	}
+}
+
+func TestWrapParagraphsWithAReadError(t *testing.T) {
+	errorFromReader := errors.New("read failed")
+	wrapper := text.NewWrapper().UsingRowWidth(10)
+
+	for _, numberOfWorkers := range []int{-1, 1, 4} {
+		reader := io.MultiReader(strings.NewReader("one\n\ntwo\n\nthree"), iotest.ErrReader(errorFromReader))
+
+		var output bytes.Buffer
+		var err error
+		if numberOfWorkers < 0 {
+			err = wrapper.WrapParagraphsFromAReader(reader, &output)
+		} else {
+			err = wrapper.WrapParagraphsFromAReaderConcurrently(reader, &output, numberOfWorkers)
+		}
+
+		if err != errorFromReader {
+			t.Errorf("(workers = %d) expected error = (%v), got = (%v)", numberOfWorkers, errorFromReader, err)
+		}
+
+		if output.String() != "one\n\ntwo\n" {
+			t.Errorf("(workers = %d) expected = (%q), got = (%q)", numberOfWorkers, "one\n\ntwo\n", output.String())
+		}
+	}
+}
+
+// writerThatFailsAfter accepts numberOfWrites writes, then fails.
+type writerThatFailsAfter struct {
+	numberOfWrites int
+	output         bytes.Buffer
+}
+
+var errorFromWriter = errors.New("write failed")
+
+func (writer *writerThatFailsAfter) Write(p []byte) (int, error) {
+	if writer.numberOfWrites == 0 {
+		return 0, errorFromWriter
+	}
+
+	writer.numberOfWrites--
+	return writer.output.Write(p)
+}
+
+func TestWrapParagraphsWithAWriteError(t *testing.T) {
+	wrapper := text.NewWrapper().UsingRowWidth(10)
+	unwrappedString := strings.Repeat("one two three four\n\n", 100)
+
+	for _, numberOfWorkers := range []int{-1, 1, 4} {
+		writer := &writerThatFailsAfter{numberOfWrites: 5}
+
+		var err error
+		if numberOfWorkers < 0 {
+			err = wrapper.WrapParagraphsFromAReader(strings.NewReader(unwrappedString), writer)
+		} else {
+			err = wrapper.WrapParagraphsFromAReaderConcurrently(strings.NewReader(unwrappedString), writer, numberOfWorkers)
+		}
+
+		if err != errorFromWriter {
+			t.Errorf("(workers = %d) expected error = (%v), got = (%v)", numberOfWorkers, errorFromWriter, err)
+		}
+
+		if writer.output.String() != "one two\nthree four\n\none two\nthree four\n" {
+			t.Errorf("(workers = %d) got = (%q)", numberOfWorkers, writer.output.String())
+		}
+	}
+}
+
+func BenchmarkWrapParagraphs(b *testing.B) {
+	unwrappedString := strings.Repeat("The quick brown fox jumps over the lazy dög, and then it runs far away into the forêt.\n\n", 2000)
+	wrapper := text.NewWrapper().UsingRowWidth(60)
+
+	for _, numberOfWorkers := range []int{-1, 0} {
+		benchmarkName := "concurrent"
+		if numberOfWorkers < 0 {
+			benchmarkName = "sequential"
+		}
+
+		b.Run(benchmarkName, func(b *testing.B) {
+			b.SetBytes(int64(len(unwrappedString)))
+
+			for i := 0; i < b.N; i++ {
+				var err error
+				if numberOfWorkers < 0 {
+					err = wrapper.WrapParagraphsFromAReader(strings.NewReader(unwrappedString), io.Discard)
+				} else {
+					err = wrapper.WrapParagraphsFromAReaderConcurrently(strings.NewReader(unwrappedString), io.Discard, numberOfWorkers)
+				}
+
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapParagraphsWithALongParagraphUnderAnInputByteLimit(t *testing.T) {
+	wrapper := text.NewWrapper().UsingRowWidth(20).UsingInputByteLimit(100)
+	expectedWrappedString := "one two\n\n" + strings.Repeat("word word word word\n", 4) + "word word word word"
+
+	testCases := []struct {
+		testName          string
+		longParagraph     string
+		expectedWrapError *text.WrapError
+	}{
+		{
+			testName:          "one long line",
+			longParagraph:     strings.Repeat("word ", 1<<20),
+			expectedWrapError: &text.WrapError{Err: text.ErrLimitExceeded, ByteOffset: 108, RuneOffset: 108, Line: 3, Row: 7},
+		},
+		{
+			testName:          "many lines",
+			longParagraph:     strings.Repeat("word word word\n", 1<<18),
+			expectedWrapError: &text.WrapError{Err: text.ErrLimitExceeded, ByteOffset: 108, RuneOffset: 108, Line: 9, Row: 7},
+		},
+	}
+
+	for _, testCase := range testCases {
+		for _, numberOfWorkers := range []int{-1, 1, 4} {
+			reader := &countingReader{source: strings.NewReader("one two\n\n" + testCase.longParagraph + "\n\nthree")}
+
+			var output bytes.Buffer
+			var err error
+			if numberOfWorkers < 0 {
+				err = wrapper.WrapParagraphsFromAReader(reader, &output)
+			} else {
+				err = wrapper.WrapParagraphsFromAReaderConcurrently(reader, &output, numberOfWorkers)
+			}
+
+			if err := errorUnlessTheExpectedWrapError(testCase.expectedWrapError, err); err != nil {
+				t.Errorf("[%s] (workers = %d) %s", testCase.testName, numberOfWorkers, err)
+			}
+
+			if output.String() != expectedWrappedString {
+				t.Errorf("[%s] (workers = %d) expected = (%q), got = (%q)", testCase.testName, numberOfWorkers, expectedWrappedString, output.String())
+			}
+
+			// the paragraph is read in pieces of up to the size of a bufio.Reader buffer
+			if reader.bytesRead > 100+2*4096 {
+				t.Errorf("[%s] (workers = %d) expected no more than %d bytes to be read, got = %d", testCase.testName, numberOfWorkers, 100+2*4096, reader.bytesRead)
+			}
+		}
+	}
+}
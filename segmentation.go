@@ -0,0 +1,402 @@
+package text
+
+import (
+	"bufio"
+	"embed"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WordSegmenter finds the words in text that is written without spaces between words, such as Thai, Lao, Khmer
+// and Myanmar. A Wrapper that has a WordSegmenter may break a row at the start of any of those words, in the same
+// way that it may break a row at whitespace (but without emitting a space).
+type WordSegmenter interface {
+	// Segments returns true if r is in a script that is divided into words by the WordSegmenter. The Wrapper
+	// passes each maximal run of such runes to WordBoundaries().
+	Segments(r rune) bool
+
+	// WordBoundaries returns the indexes in run of the runes that start a word, not including the first rune.
+	WordBoundaries(run []rune) []int
+}
+
+//go:embed dictionaries/*.txt
+var embeddedDictionaryFiles embed.FS
+
+// scriptsWithEmbeddedDictionaries are the scripts that are divided into words by NewDictionaryWordSegmenter(), with
+// the name of the embedded dictionary file for each.
+var scriptsWithEmbeddedDictionaries = []struct {
+	script         *unicode.RangeTable
+	dictionaryFile string
+}{
+	{unicode.Thai, "dictionaries/thai.txt"},
+	{unicode.Lao, "dictionaries/lao.txt"},
+	{unicode.Khmer, "dictionaries/khmer.txt"},
+	{unicode.Myanmar, "dictionaries/myanmar.txt"},
+}
+
+// SegmentationDictionary is the list of words used by a DictionaryWordSegmenter for one script.
+type SegmentationDictionary struct {
+	words                 map[string]bool
+	lengthOfLongestWord   int
+	lengthOfWordIsCounted map[int]int
+}
+
+// NewSegmentationDictionary creates a SegmentationDictionary with no words.
+func NewSegmentationDictionary() *SegmentationDictionary {
+	return &SegmentationDictionary{
+		words:                 make(map[string]bool),
+		lengthOfLongestWord:   0,
+		lengthOfWordIsCounted: make(map[int]int),
+	}
+}
+
+// AddWords adds words to the dictionary.
+func (dictionary *SegmentationDictionary) AddWords(words ...string) *SegmentationDictionary {
+	for _, word := range words {
+		if word == "" || dictionary.words[word] {
+			continue
+		}
+
+		dictionary.words[word] = true
+
+		lengthOfWord := len([]rune(word))
+		dictionary.lengthOfWordIsCounted[lengthOfWord]++
+		if lengthOfWord > dictionary.lengthOfLongestWord {
+			dictionary.lengthOfLongestWord = lengthOfWord
+		}
+	}
+
+	return dictionary
+}
+
+// RemoveWords removes words from the dictionary. Words that are not in the dictionary are ignored.
+func (dictionary *SegmentationDictionary) RemoveWords(words ...string) *SegmentationDictionary {
+	for _, word := range words {
+		if !dictionary.words[word] {
+			continue
+		}
+
+		delete(dictionary.words, word)
+
+		lengthOfWord := len([]rune(word))
+		dictionary.lengthOfWordIsCounted[lengthOfWord]--
+		if dictionary.lengthOfWordIsCounted[lengthOfWord] == 0 {
+			delete(dictionary.lengthOfWordIsCounted, lengthOfWord)
+			if lengthOfWord == dictionary.lengthOfLongestWord {
+				dictionary.lengthOfLongestWord = 0
+				for countedLength := range dictionary.lengthOfWordIsCounted {
+					if countedLength > dictionary.lengthOfLongestWord {
+						dictionary.lengthOfLongestWord = countedLength
+					}
+				}
+			}
+		}
+	}
+
+	return dictionary
+}
+
+// LoadWordsFrom reads a word list from reader and applies it to the dictionary. Each line has one word, which is
+// added to the dictionary. A line that starts with '-' removes the word that follows the '-' instead. Whitespace
+// around each line is ignored, as are empty lines and lines that start with '#'.
+func (dictionary *SegmentationDictionary) LoadWordsFrom(reader io.Reader) error {
+	lineScanner := bufio.NewScanner(reader)
+
+	for lineScanner.Scan() {
+		line := strings.TrimSpace(lineScanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "-"):
+			dictionary.RemoveWords(strings.TrimSpace(line[1:]))
+		default:
+			dictionary.AddWords(line)
+		}
+	}
+
+	return lineScanner.Err()
+}
+
+// Contains returns true if word is in the dictionary.
+func (dictionary *SegmentationDictionary) Contains(word string) bool {
+	return dictionary.words[word]
+}
+
+type scriptAndDictionary struct {
+	script     *unicode.RangeTable
+	dictionary *SegmentationDictionary
+}
+
+// DictionaryWordSegmenter is a WordSegmenter that finds words using a dictionary for each script. A run of text in
+// one script is divided into the sequence of dictionary words and unknown characters that has the fewest unknown
+// characters and, of those, the fewest words. An unknown character is a character cluster: a consonant or
+// independent vowel with the marks and vowel signs that are written with it. Text that is not in the dictionary may
+// be broken between any two clusters, even in the middle of a syllable, so that a row never has to be broken inside
+// a cluster. A word never starts with a combining mark, a vowel sign that is written after its consonant (such as
+// Thai SARA AA), or a character that follows a virama or coeng, and never ends with a vowel sign that is written
+// before its consonant (such as Thai SARA E).
+//
+// Rows are broken between words only as well as the dictionaries cover the words of the text, so wrapping real text
+// needs a full word list for its language, such as the ones from libthai or ICU.
+type DictionaryWordSegmenter struct {
+	dictionaries []*scriptAndDictionary
+}
+
+// NewDictionaryWordSegmenter creates a DictionaryWordSegmenter for Thai, Lao, Khmer and Myanmar, using the
+// dictionaries embedded in this package. The embedded dictionaries are not full dictionaries: each is a starter list
+// of fewer than a hundred common words, so most words of real text are not in them, and are broken between character
+// clusters rather than between words. Load a full word list with DictionaryFor() and
+// SegmentationDictionary.LoadWordsFrom(), or replace a dictionary with UsingDictionaryFor().
+func NewDictionaryWordSegmenter() *DictionaryWordSegmenter {
+	segmenter := &DictionaryWordSegmenter{
+		dictionaries: make([]*scriptAndDictionary, 0, len(scriptsWithEmbeddedDictionaries)),
+	}
+
+	for _, scriptWithDictionary := range scriptsWithEmbeddedDictionaries {
+		dictionaryFile, err := embeddedDictionaryFiles.Open(scriptWithDictionary.dictionaryFile)
+		if err != nil {
+			panic(err)
+		}
+
+		dictionary := NewSegmentationDictionary()
+		if err := dictionary.LoadWordsFrom(dictionaryFile); err != nil {
+			panic(err)
+		}
+		dictionaryFile.Close()
+
+		segmenter.UsingDictionaryFor(scriptWithDictionary.script, dictionary)
+	}
+
+	return segmenter
+}
+
+// UsingDictionaryFor sets the dictionary used for text in script (for example, unicode.Thai), replacing any
+// dictionary already set for it. Runs of text in script are then segmented by the DictionaryWordSegmenter.
+func (segmenter *DictionaryWordSegmenter) UsingDictionaryFor(script *unicode.RangeTable, dictionary *SegmentationDictionary) *DictionaryWordSegmenter {
+	for _, existingDictionary := range segmenter.dictionaries {
+		if existingDictionary.script == script {
+			existingDictionary.dictionary = dictionary
+			return segmenter
+		}
+	}
+
+	segmenter.dictionaries = append(segmenter.dictionaries, &scriptAndDictionary{script: script, dictionary: dictionary})
+	return segmenter
+}
+
+// DictionaryFor returns the dictionary used for text in script, or nil if there is none. Changes to the returned
+// dictionary affect the DictionaryWordSegmenter.
+func (segmenter *DictionaryWordSegmenter) DictionaryFor(script *unicode.RangeTable) *SegmentationDictionary {
+	for _, existingDictionary := range segmenter.dictionaries {
+		if existingDictionary.script == script {
+			return existingDictionary.dictionary
+		}
+	}
+
+	return nil
+}
+
+// Segments returns true if r is in a script that has a dictionary.
+func (segmenter *DictionaryWordSegmenter) Segments(r rune) bool {
+	return segmenter.dictionaryIndexFor(r) >= 0
+}
+
+// WordBoundaries returns the indexes of the runes that start each word in run, other than the first. Where run
+// changes from one script to another, there is always a word boundary.
+func (segmenter *DictionaryWordSegmenter) WordBoundaries(run []rune) []int {
+	boundaries := make([]int, 0, len(run)/3)
+
+	for startOfScriptRun := 0; startOfScriptRun < len(run); {
+		dictionaryIndex := segmenter.dictionaryIndexFor(run[startOfScriptRun])
+
+		endOfScriptRun := startOfScriptRun + 1
+		for endOfScriptRun < len(run) && (segmenter.dictionaryIndexFor(run[endOfScriptRun]) == dictionaryIndex || runeExtendsPreviousCharacter(run[endOfScriptRun])) {
+			endOfScriptRun++
+		}
+
+		if startOfScriptRun > 0 {
+			boundaries = append(boundaries, startOfScriptRun)
+		}
+
+		if dictionaryIndex >= 0 {
+			for _, boundary := range segmenter.dictionaries[dictionaryIndex].dictionary.wordBoundariesIn(run[startOfScriptRun:endOfScriptRun]) {
+				boundaries = append(boundaries, startOfScriptRun+boundary)
+			}
+		}
+
+		startOfScriptRun = endOfScriptRun
+	}
+
+	return boundaries
+}
+
+func (segmenter *DictionaryWordSegmenter) dictionaryIndexFor(r rune) int {
+	for dictionaryIndex, scriptWithDictionary := range segmenter.dictionaries {
+		if unicode.Is(scriptWithDictionary.script, r) {
+			return dictionaryIndex
+		}
+	}
+
+	return -1
+}
+
+// segmentationCost is compared first by the number of unknown characters, then by the number of words.
+type segmentationCost struct {
+	numberOfUnknownCharacters int
+	numberOfWords             int
+}
+
+func (cost segmentationCost) isLessThan(otherCost segmentationCost) bool {
+	if cost.numberOfUnknownCharacters != otherCost.numberOfUnknownCharacters {
+		return cost.numberOfUnknownCharacters < otherCost.numberOfUnknownCharacters
+	}
+
+	return cost.numberOfWords < otherCost.numberOfWords
+}
+
+// wordBoundariesIn finds the lowest cost division of run into dictionary words and unknown characters, and
+// returns the indexes at which words and unknown characters start (other than 0).
+func (dictionary *SegmentationDictionary) wordBoundariesIn(run []rune) []int {
+	canStartAWord := make([]bool, len(run)+1)
+	canStartAWord[0] = true
+	canStartAWord[len(run)] = true
+	for runeIndex := 1; runeIndex < len(run); runeIndex++ {
+		canStartAWord[runeIndex] = wordMayStartBetween(run[runeIndex-1], run[runeIndex]) && !startsAFinalConsonant(run[runeIndex:])
+	}
+
+	const unreachable = -1
+	costToReach := make([]segmentationCost, len(run)+1)
+	startOfLastSegment := make([]int, len(run)+1)
+	for runeIndex := 1; runeIndex <= len(run); runeIndex++ {
+		startOfLastSegment[runeIndex] = unreachable
+	}
+
+	for startIndex := 0; startIndex < len(run); startIndex++ {
+		if !canStartAWord[startIndex] || startOfLastSegment[startIndex] == unreachable && startIndex > 0 {
+			continue
+		}
+
+		for endIndex := startIndex + 1; endIndex <= len(run) && endIndex-startIndex <= dictionary.lengthOfLongestWord; endIndex++ {
+			if !dictionary.words[string(run[startIndex:endIndex])] {
+				continue
+			}
+
+			endOfWord := endIndex
+			for endOfWord < len(run) && isWrittenAfterAWord(run[endOfWord]) {
+				endOfWord++
+			}
+
+			if canStartAWord[endOfWord] {
+				cost := segmentationCost{costToReach[startIndex].numberOfUnknownCharacters, costToReach[startIndex].numberOfWords + 1}
+				if startOfLastSegment[endOfWord] == unreachable || cost.isLessThan(costToReach[endOfWord]) {
+					costToReach[endOfWord] = cost
+					startOfLastSegment[endOfWord] = startIndex
+				}
+			}
+		}
+
+		endOfUnknownCharacter := startIndex + 1
+		for !canStartAWord[endOfUnknownCharacter] {
+			endOfUnknownCharacter++
+		}
+
+		cost := segmentationCost{costToReach[startIndex].numberOfUnknownCharacters + 1, costToReach[startIndex].numberOfWords + 1}
+		if startOfLastSegment[endOfUnknownCharacter] == unreachable || cost.isLessThan(costToReach[endOfUnknownCharacter]) {
+			costToReach[endOfUnknownCharacter] = cost
+			startOfLastSegment[endOfUnknownCharacter] = startIndex
+		}
+	}
+
+	boundaries := make([]int, 0, len(run)/3)
+	for endIndex := len(run); endIndex > 0; endIndex = startOfLastSegment[endIndex] {
+		if startIndex := startOfLastSegment[endIndex]; startIndex > 0 {
+			boundaries = append(boundaries, startIndex)
+		}
+	}
+
+	for i, j := 0, len(boundaries)-1; i < j; i, j = i+1, j-1 {
+		boundaries[i], boundaries[j] = boundaries[j], boundaries[i]
+	}
+
+	return boundaries
+}
+
+// wordMayStartBetween returns false if a word cannot start with nextRune when it follows previousRune: if nextRune
+// is a combining mark, a vowel or semivowel sign that is written after its consonant, or a repetition, abbreviation
+// or sentence mark that is written after a word, if previousRune is a vowel sign that is written before its
+// consonant, a virama or a coeng, or if both are digits.
+func wordMayStartBetween(previousRune rune, nextRune rune) bool {
+	if runeExtendsPreviousCharacter(nextRune) || unicode.In(nextRune, unicode.Mc) {
+		return false
+	}
+
+	switch nextRune {
+	case 'ะ', 'า', 'ำ', 'ๅ', 'ະ', 'າ', 'ຳ', 'ຽ':
+		return false
+	}
+
+	if isWrittenAfterAWord(nextRune) {
+		return false
+	}
+
+	switch {
+	case unicode.IsDigit(previousRune) && unicode.IsDigit(nextRune):
+		return false
+	case previousRune >= 'เ' && previousRune <= 'ไ':
+		return false
+	case previousRune >= 'ເ' && previousRune <= 'ໄ':
+		return false
+	case previousRune == '្' || previousRune == '္':
+		return false
+	}
+
+	return true
+}
+
+// isWrittenAfterAWord returns true if r is a repetition, abbreviation or sentence mark that is written after a word,
+// and is kept with the word before it.
+func isWrittenAfterAWord(r rune) bool {
+	switch r {
+	case 'ฯ', 'ๆ', 'ໆ', '។', '៕', '၊', '။':
+		return true
+	}
+
+	return false
+}
+
+// startsAFinalConsonant returns true if runes starts with a Myanmar consonant that is killed by an asat, which is the
+// final consonant of the syllable before it. A consonant killed by an asat and followed by a virama (a kinzi) is
+// written with the syllable after it instead.
+func startsAFinalConsonant(runes []rune) bool {
+	if len(runes) < 2 || runes[0] < 'က' || runes[0] > 'အ' {
+		return false
+	}
+
+	indexOfAsat := 1
+	if runes[indexOfAsat] == '့' && len(runes) > 2 {
+		indexOfAsat++
+	}
+
+	if runes[indexOfAsat] != '်' {
+		return false
+	}
+
+	return indexOfAsat+1 >= len(runes) || runes[indexOfAsat+1] != '္'
+}
+
+// ChangeWordSegmenterTo sets the WordSegmenter used to find words in text that is written without spaces between
+// words. If it is nil (the default), such text is treated as a single word, and is broken at the end of the row
+// like any word that is too long for a row.
+func (wrapper *Wrapper) ChangeWordSegmenterTo(segmenter WordSegmenter) *Wrapper {
+	wrapper.wordSegmenter = segmenter
+	return wrapper
+}
+
+// UsingWordSegmenter is the same as ChangeWordSegmenterTo(), but provides a more readable name if this is chained
+// with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingWordSegmenter(text.NewDictionaryWordSegmenter())
+func (wrapper *Wrapper) UsingWordSegmenter(segmenter WordSegmenter) *Wrapper {
+	return wrapper.ChangeWordSegmenterTo(segmenter)
+}
@@ -0,0 +1,311 @@
+package text_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/blorticus-go/text"
+)
+
+type WordSegmenterTestCase struct {
+	testName         string
+	segmenter        text.WordSegmenter
+	run              string
+	expectedSegments []string
+}
+
+func (testCase *WordSegmenterTestCase) RunTest() error {
+	runes := []rune(testCase.run)
+
+	segments := make([]string, 0, len(testCase.expectedSegments))
+	startOfSegment := 0
+	for _, boundary := range testCase.segmenter.WordBoundaries(runes) {
+		segments = append(segments, string(runes[startOfSegment:boundary]))
+		startOfSegment = boundary
+	}
+	segments = append(segments, string(runes[startOfSegment:]))
+
+	expected := strings.Join(testCase.expectedSegments, "|")
+	got := strings.Join(segments, "|")
+	if got != expected {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, expected, got)
+	}
+
+	return nil
+}
+
+type SegmentedWrapTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	unwrappedString       string
+	expectedWrappedString string
+}
+
+func (testCase *SegmentedWrapTestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapStringText(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestDictionaryWordSegmenter(t *testing.T) {
+	segmenter := text.NewDictionaryWordSegmenter()
+
+	segmenterWithAddedWords := text.NewDictionaryWordSegmenter()
+	segmenterWithAddedWords.DictionaryFor(unicode.Thai).AddWords("ภาษาไทย")
+
+	segmenterWithReplacedDictionary := text.NewDictionaryWordSegmenter().
+		UsingDictionaryFor(unicode.Thai, text.NewSegmentationDictionary().AddWords("ฉันรัก", "เมืองไทย"))
+
+	testCases := []*WordSegmenterTestCase{
+		{
+			testName:         "Thai",
+			segmenter:        segmenter,
+			run:              "ภาษาไทยง่ายนิดเดียว",
+			expectedSegments: []string{"ภาษา", "ไทย", "ง่าย", "นิด", "เดียว"},
+		},
+		{
+			testName:         "Thai with leading vowels",
+			segmenter:        segmenter,
+			run:              "ฉันรักเมืองไทย",
+			expectedSegments: []string{"ฉัน", "รัก", "เมือง", "ไทย"},
+		},
+		{
+			testName:         "Thai with unknown characters",
+			segmenter:        segmenter,
+			run:              "ฉันกขคไทย",
+			expectedSegments: []string{"ฉัน", "ก", "ข", "ค", "ไทย"},
+		},
+		{
+			testName:         "Thai word that is not in the dictionary is divided into clusters",
+			segmenter:        segmenter,
+			run:              "กระต่ายกินผักเมื่อเช้า",
+			expectedSegments: []string{"ก", "ระ", "ต่า", "ย", "กิน", "ผั", "ก", "เมื่", "อ", "เช้า"},
+		},
+		{
+			testName:         "Lao",
+			segmenter:        segmenter,
+			run:              "ສະບາຍດີ",
+			expectedSegments: []string{"ສະບາຍ", "ດີ"},
+		},
+		{
+			testName:         "Lao language name",
+			segmenter:        segmenter,
+			run:              "ພາສາລາວ",
+			expectedSegments: []string{"ພາສາ", "ລາວ"},
+		},
+		{
+			testName:         "Khmer with coeng",
+			segmenter:        segmenter,
+			run:              "ខ្ញុំស្រឡាញ់ភាសាខ្មែរ",
+			expectedSegments: []string{"ខ្ញុំ", "ស្រឡាញ់", "ភាសា", "ខ្មែរ"},
+		},
+		{
+			testName:         "Myanmar",
+			segmenter:        segmenter,
+			run:              "မြန်မာစာ",
+			expectedSegments: []string{"မြန်မာ", "စာ"},
+		},
+		{
+			testName:         "Myanmar with virama",
+			segmenter:        segmenter,
+			run:              "မင်္ဂလာပါ",
+			expectedSegments: []string{"မင်္ဂလာ", "ပါ"},
+		},
+		{
+			testName:         "change of script",
+			segmenter:        segmenter,
+			run:              "ภาษาລາວ",
+			expectedSegments: []string{"ภาษา", "ລາວ"},
+		},
+		{
+			testName:         "added word",
+			segmenter:        segmenterWithAddedWords,
+			run:              "ภาษาไทยง่าย",
+			expectedSegments: []string{"ภาษาไทย", "ง่าย"},
+		},
+		{
+			testName:         "replaced dictionary",
+			segmenter:        segmenterWithReplacedDictionary,
+			run:              "ฉันรักเมืองไทย",
+			expectedSegments: []string{"ฉันรัก", "เมืองไทย"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestSegmentationDictionaryLoadWordsFrom(t *testing.T) {
+	dictionary := text.NewSegmentationDictionary().AddWords("ไทย", "ภาษา")
+
+	wordList := "# a word list\n\nนิด\n  เดียว  \n-ภาษา\n"
+	if err := dictionary.LoadWordsFrom(strings.NewReader(wordList)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for word, expectedToBeContained := range map[string]bool{
+		"ไทย":           true,
+		"นิด":           true,
+		"เดียว":         true,
+		"ภาษา":          false,
+		"# a word list": false,
+		"":              false,
+	} {
+		if dictionary.Contains(word) != expectedToBeContained {
+			t.Errorf("for (%q) expected Contains() = (%t), got = (%t)", word, expectedToBeContained, !expectedToBeContained)
+		}
+	}
+}
+
+func TestWrapUsingWordSegmenter(t *testing.T) {
+	segmenter := text.NewDictionaryWordSegmenter()
+
+	testCases := []*SegmentedWrapTestCase{
+		{
+			testName:              "without a segmenter Thai is one long word",
+			wrapper:               text.NewWrapper().UsingRowWidth(12),
+			unwrappedString:       "Hello ภาษาไทยง่ายนิดเดียว world ฉันรักเมืองไทย",
+			expectedWrappedString: "Hello\nภาษาไทยง่ายน\nิดเดียว\nworld\nฉันรักเมืองไ\nทย",
+		},
+		{
+			testName:              "mixed English and Thai",
+			wrapper:               text.NewWrapper().UsingRowWidth(12).UsingWordSegmenter(segmenter),
+			unwrappedString:       "Hello ภาษาไทยง่ายนิดเดียว world ฉันรักเมืองไทย",
+			expectedWrappedString: "Hello ภาษา\nไทยง่ายนิด\nเดียว world\nฉันรักเมือง\nไทย",
+		},
+		{
+			testName:              "Khmer with combining marks measured as zero columns",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingMeasurer(text.EastAsianNarrowMeasurer).UsingWordSegmenter(segmenter),
+			unwrappedString:       "ខ្ញុំស្រឡាញ់ភាសាខ្មែរ",
+			expectedWrappedString: "ខ្ញុំស្រឡាញ់\nភាសាខ្មែរ",
+		},
+		{
+			testName:              "Thai words that are not in the dictionary are broken between clusters",
+			wrapper:               text.NewWrapper().UsingRowWidth(7).UsingWordSegmenter(segmenter),
+			unwrappedString:       "เด็กๆชอบกินน้ำแข็งใสเย็นเจี๊ยบ",
+			expectedWrappedString: "เด็กๆ\nชอบกิน\nน้ำแข็ง\nใสเย็น\nเจี๊ยบ",
+		},
+		{
+			testName:              "Lao and English",
+			wrapper:               text.NewWrapper().UsingRowWidth(9).UsingWordSegmenter(segmenter),
+			unwrappedString:       "Lao: ພາສາລາວ ສະບາຍດີ",
+			expectedWrappedString: "Lao: ພາສາ\nລາວ ສະບາຍ\nດີ",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func isNotASCII(r rune) bool {
+	return r > unicode.MaxASCII
+}
+
+// errorUnlessClustersAreKeptWhole returns an error if a row of wrappedString starts with a character that is
+// written with the character before it, or if a number is broken across rows.
+func errorUnlessClustersAreKeptWhole(unwrappedString string, wrappedString string) error {
+	rows := strings.Split(wrappedString, "\n")
+	for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
+		runesInPreviousRow, runesInRow := []rune(rows[rowIndex-1]), []rune(rows[rowIndex])
+		if len(runesInPreviousRow) == 0 || len(runesInRow) == 0 {
+			continue
+		}
+
+		lastRuneOfPreviousRow, firstRune := runesInPreviousRow[len(runesInPreviousRow)-1], runesInRow[0]
+		if !strings.Contains(unwrappedString, string([]rune{lastRuneOfPreviousRow, firstRune})) {
+			continue
+		}
+
+		switch {
+		case unicode.In(firstRune, unicode.Mn, unicode.Mc) || strings.ContainsRune("ะาำๅະາຳຽฯๆໆ។៕၊။", firstRune):
+			return fmt.Errorf("row (%q) starts inside a character cluster", rows[rowIndex])
+		case strings.ContainsRune("เแโใไເແໂໃໄ្္", lastRuneOfPreviousRow):
+			return fmt.Errorf("row (%q) ends inside a character cluster", rows[rowIndex-1])
+		case unicode.Is(unicode.Myanmar, firstRune) && (strings.HasPrefix(string(runesInRow[1:]), "်") || strings.HasPrefix(string(runesInRow[1:]), "့်")):
+			return fmt.Errorf("row (%q) starts with the final consonant of a syllable", rows[rowIndex])
+		case unicode.IsDigit(lastRuneOfPreviousRow) && unicode.IsDigit(firstRune):
+			return fmt.Errorf("number is broken between rows (%q) and (%q)", rows[rowIndex-1], rows[rowIndex])
+		}
+	}
+
+	return nil
+}
+
+func TestWrapSentencesUsingWordSegmenter(t *testing.T) {
+	segmenter := text.NewDictionaryWordSegmenter()
+
+	testCases := []struct {
+		testName        string
+		rowWidth        uint
+		unwrappedString string
+	}{
+		{
+			testName:        "Thai",
+			rowWidth:        10,
+			unwrappedString: "The Thai name of Bangkok is กรุงเทพมหานคร อมรรัตนโกสินทร์ มหินทรายุธยา มหาดิลกภพ and it is usually shortened to กรุงเทพฯ",
+		},
+		{
+			testName:        "Thai with numbers",
+			rowWidth:        12,
+			unwrappedString: "ประเทศไทยมีประชากรประมาณ 66 ล้านคน (2020 census) และมีพื้นที่ 513,120 ตารางกิโลเมตร",
+		},
+		{
+			testName:        "Lao",
+			rowWidth:        10,
+			unwrappedString: "Vientiane, ນະຄອນຫຼວງວຽງຈັນ, is the capital of ສາທາລະນະລັດ ປະຊາທິປະໄຕ ປະຊາຊົນລາວ",
+		},
+		{
+			testName:        "Khmer",
+			rowWidth:        10,
+			unwrappedString: "ភ្នំពេញជារាជធានីនៃព្រះរាជាណាចក្រកម្ពុជា (Phnom Penh is the capital of Cambodia)",
+		},
+		{
+			testName:        "Myanmar",
+			rowWidth:        12,
+			unwrappedString: "Naypyidaw (နေပြည်တော်) သည် မြန်မာနိုင်ငံ၏ မြို့တော်ဖြစ်ပြီး ၂၀၀၅ ခုနှစ်တွင် တည်ထောင်ခဲ့သည်။",
+		},
+	}
+
+	for _, testCase := range testCases {
+		wrappedString, err := text.NewWrapper().UsingRowWidth(testCase.rowWidth).UsingWordSegmenter(segmenter).WrapStringText(testCase.unwrappedString)
+		if err != nil {
+			t.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+			continue
+		}
+
+		if strings.Join(strings.Fields(wrappedString), "") != strings.Join(strings.Fields(testCase.unwrappedString), "") {
+			t.Errorf("[%s] expected the wrapped text to have the characters of (%q), got = (%q)", testCase.testName, testCase.unwrappedString, wrappedString)
+		}
+
+		for _, row := range strings.Split(wrappedString, "\n") {
+			if uint(len([]rune(row))) > testCase.rowWidth {
+				t.Errorf("[%s] expected rows no wider than %d, got = (%q)", testCase.testName, testCase.rowWidth, row)
+			}
+		}
+
+		for _, field := range strings.Fields(testCase.unwrappedString) {
+			if strings.IndexFunc(field, isNotASCII) < 0 && len(field) <= int(testCase.rowWidth) && !strings.Contains(wrappedString, field) {
+				t.Errorf("[%s] expected (%q) to be kept in one row, got = (%q)", testCase.testName, field, wrappedString)
+			}
+		}
+
+		if err := errorUnlessClustersAreKeptWhole(testCase.unwrappedString, wrappedString); err != nil {
+			t.Errorf("[%s] %s", testCase.testName, err)
+		}
+	}
+}
@@ -0,0 +1,548 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableBorderStyle determines the characters that a Table uses to draw borders around and between cells.
+type TableBorderStyle int
+
+const (
+	// ASCIITableBorders draws borders using '+', '-' and '|'.
+	ASCIITableBorders TableBorderStyle = iota
+
+	// UnicodeBoxDrawingTableBorders draws borders using Unicode box-drawing characters.
+	UnicodeBoxDrawingTableBorders
+
+	// MarkdownTableBorders draws a Markdown pipe table. A Markdown table must have a header row, so if one is
+	// not set, an empty header row is drawn. Markdown table rows cannot span more than one line, so each wrapped
+	// row of a cell becomes a separate table row. Any '|' in a cell is escaped as "\|".
+	MarkdownTableBorders
+
+	// NoTableBorders draws no borders. Columns are separated by two spaces.
+	NoTableBorders
+)
+
+// TableColumn describes how a single Table column is sized and aligned. By default, a column has no fixed
+// width, a minimum width of 1, no maximum width, no proportional weight and left alignment.
+type TableColumn struct {
+	fixedWidth         uint
+	minimumWidth       uint
+	maximumWidth       uint
+	proportionalWeight uint
+	alignment          Alignment
+}
+
+// NewTableColumn creates a TableColumn with the default sizing and alignment.
+func NewTableColumn() *TableColumn {
+	return &TableColumn{
+		fixedWidth:         0,
+		minimumWidth:       1,
+		maximumWidth:       0,
+		proportionalWeight: 0,
+		alignment:          AlignLeft,
+	}
+}
+
+// UsingFixedWidth sets the width of the column, regardless of its contents or the total width of the Table.
+// A value of 0 means the width is not fixed.
+func (column *TableColumn) UsingFixedWidth(numberOfColumns uint) *TableColumn {
+	column.fixedWidth = numberOfColumns
+	return column
+}
+
+// UsingMinimumWidth sets the narrowest width to which a column may shrink when the Table is wider than its total
+// width. The minimum width must be at least 1.
+func (column *TableColumn) UsingMinimumWidth(numberOfColumns uint) *TableColumn {
+	if numberOfColumns == 0 {
+		panic("TableColumn minimum width must be at least 1")
+	}
+
+	column.minimumWidth = numberOfColumns
+	return column
+}
+
+// UsingMaximumWidth sets the widest width of the column. Cell text that is wider than this is wrapped. A value of
+// 0 means there is no maximum.
+func (column *TableColumn) UsingMaximumWidth(numberOfColumns uint) *TableColumn {
+	column.maximumWidth = numberOfColumns
+	return column
+}
+
+// UsingProportionalWeight makes the column proportional. Rather than being sized to fit its contents, a
+// proportional column shares the width that remains after all other columns are sized with the other
+// proportional columns, in proportion to its weight. A value of 0 means the column is not proportional.
+func (column *TableColumn) UsingProportionalWeight(weight uint) *TableColumn {
+	column.proportionalWeight = weight
+	return column
+}
+
+// UsingAlignment sets the alignment of the text in the column's cells.
+func (column *TableColumn) UsingAlignment(alignment Alignment) *TableColumn {
+	column.alignment = alignment
+	return column
+}
+
+// Table renders rows of text cells as a plain-text table. The width of each column is computed so that the table
+// fits within a (configurable) total width, including borders. A column is as wide as its widest cell, within the
+// minimum and maximum set for the column, unless it has a fixed width or is proportional (see TableColumn). If the
+// columns do not fit in the total width, the widest columns that are above their minimum width are narrowed one
+// column at a time until they do. The text in each cell is then wrapped to the column width using a Wrapper, and
+// each row of the Table is as tall as its tallest cell.
+type Table struct {
+	columns         []*TableColumn
+	headerRowCells  []string
+	bodyRows        [][]string
+	totalWidth      uint
+	borderStyle     TableBorderStyle
+	wrapperForCells *Wrapper
+}
+
+// NewTable creates an empty Table. By default, the total width is 79, the borders are ASCIITableBorders, and
+// cells are wrapped using a Wrapper created with NewWrapper().
+func NewTable() *Table {
+	return &Table{
+		columns:         nil,
+		headerRowCells:  nil,
+		bodyRows:        make([][]string, 0, 10),
+		totalWidth:      79,
+		borderStyle:     ASCIITableBorders,
+		wrapperForCells: NewWrapper(),
+	}
+}
+
+// ChangeTotalWidthTo changes the total width of the Table, including borders.
+func (table *Table) ChangeTotalWidthTo(numberOfColumns uint) *Table {
+	table.totalWidth = numberOfColumns
+	return table
+}
+
+// UsingTotalWidth is the same as ChangeTotalWidthTo(), but provides a more readable name if this is chained with
+// the constructor.
+func (table *Table) UsingTotalWidth(numberOfColumns uint) *Table {
+	return table.ChangeTotalWidthTo(numberOfColumns)
+}
+
+// ChangeBorderStyleTo changes the style of the Table borders. If style is not one of the TableBorderStyle constants,
+// Render() returns an error.
+func (table *Table) ChangeBorderStyleTo(style TableBorderStyle) *Table {
+	table.borderStyle = style
+	return table
+}
+
+// UsingBorderStyle is the same as ChangeBorderStyleTo(), but provides a more readable name if this is chained
+// with the constructor.
+func (table *Table) UsingBorderStyle(style TableBorderStyle) *Table {
+	return table.ChangeBorderStyleTo(style)
+}
+
+// ChangeColumnsTo sets the sizing and alignment of the Table columns, from left to right. If a row has more cells
+// than there are TableColumns, the extra columns use the defaults described for NewTableColumn().
+func (table *Table) ChangeColumnsTo(columns ...*TableColumn) *Table {
+	table.columns = columns
+	return table
+}
+
+// UsingColumns is the same as ChangeColumnsTo(), but provides a more readable name if this is chained with the
+// constructor.
+func (table *Table) UsingColumns(columns ...*TableColumn) *Table {
+	return table.ChangeColumnsTo(columns...)
+}
+
+// ChangeHeaderRowTo sets the cells of the header row. The header row is separated from the body rows by a
+// border (except when the border style is NoTableBorders).
+func (table *Table) ChangeHeaderRowTo(cells ...string) *Table {
+	table.headerRowCells = cells
+	return table
+}
+
+// UsingHeaderRow is the same as ChangeHeaderRowTo(), but provides a more readable name if this is chained with
+// the constructor.
+func (table *Table) UsingHeaderRow(cells ...string) *Table {
+	return table.ChangeHeaderRowTo(cells...)
+}
+
+// ChangeWrapperTo sets the Wrapper used to wrap the text in each cell. This can be used to set row indents for
+// cells, or a Measurer for cell text (borders are always measured with StringWidth()). The row width of the provided
+// Wrapper is ignored, and the Wrapper itself is not changed.
+func (table *Table) ChangeWrapperTo(wrapper *Wrapper) *Table {
+	table.wrapperForCells = wrapper
+	return table
+}
+
+// UsingWrapper is the same as ChangeWrapperTo(), but provides a more readable name if this is chained with the
+// constructor.
+func (table *Table) UsingWrapper(wrapper *Wrapper) *Table {
+	return table.ChangeWrapperTo(wrapper)
+}
+
+// AppendRow adds a body row to the end of the Table. A row may have fewer cells than the Table has columns, in
+// which case the missing cells are empty.
+func (table *Table) AppendRow(cells ...string) *Table {
+	table.bodyRows = append(table.bodyRows, cells)
+	return table
+}
+
+// Render computes the column widths, wraps the text in every cell, and returns the Table as text. Rows are
+// separated by a newline, and there is no newline after the last row. It returns an error if the columns cannot
+// fit in the total width, if wrapping a cell fails, or if the border style is unknown.
+func (table *Table) Render() (renderedTable string, err error) {
+	borders, borderStyleIsKnown := tableBordersFor[table.borderStyle]
+	if !borderStyleIsKnown {
+		return "", fmt.Errorf("unknown table border style (%d)", table.borderStyle)
+	}
+
+	numberOfColumns := table.numberOfColumns()
+	if numberOfColumns == 0 {
+		return "", nil
+	}
+
+	columnWidths, err := table.computeColumnWidths(numberOfColumns, borders)
+	if err != nil {
+		return "", err
+	}
+
+	var renderedRows []string
+
+	if borders.hasOuterRules {
+		renderedRows = append(renderedRows, borders.ruleUsing(borders.topRule, columnWidths))
+	}
+
+	if table.headerRowCells != nil || table.borderStyle == MarkdownTableBorders {
+		headerRows, err := table.renderRowOfCells(table.headerRowCells, columnWidths, borders)
+		if err != nil {
+			return "", err
+		}
+
+		renderedRows = append(renderedRows, headerRows...)
+
+		if table.borderStyle == MarkdownTableBorders {
+			renderedRows = append(renderedRows, table.markdownAlignmentRule(columnWidths))
+		} else if table.borderStyle != NoTableBorders {
+			renderedRows = append(renderedRows, borders.ruleUsing(borders.middleRule, columnWidths))
+		}
+	}
+
+	for _, bodyRow := range table.bodyRows {
+		bodyRows, err := table.renderRowOfCells(bodyRow, columnWidths, borders)
+		if err != nil {
+			return "", err
+		}
+
+		renderedRows = append(renderedRows, bodyRows...)
+	}
+
+	if borders.hasOuterRules {
+		renderedRows = append(renderedRows, borders.ruleUsing(borders.bottomRule, columnWidths))
+	}
+
+	return strings.Join(renderedRows, "\n"), nil
+}
+
+func (table *Table) numberOfColumns() int {
+	numberOfColumns := len(table.headerRowCells)
+
+	for _, bodyRow := range table.bodyRows {
+		if len(bodyRow) > numberOfColumns {
+			numberOfColumns = len(bodyRow)
+		}
+	}
+
+	if numberOfColumns > 0 && len(table.columns) > numberOfColumns {
+		numberOfColumns = len(table.columns)
+	}
+
+	return numberOfColumns
+}
+
+func (table *Table) columnAt(columnIndex int) *TableColumn {
+	if columnIndex < len(table.columns) && table.columns[columnIndex] != nil {
+		return table.columns[columnIndex]
+	}
+
+	return NewTableColumn()
+}
+
+func (table *Table) cellTextAsRendered(cellText string) string {
+	if table.borderStyle == MarkdownTableBorders {
+		return strings.ReplaceAll(cellText, "|", "\\|")
+	}
+
+	return cellText
+}
+
+func (table *Table) widthOfWidestCellInColumn(columnIndex int) int {
+	widestCell := 0
+
+	for _, row := range append([][]string{table.headerRowCells}, table.bodyRows...) {
+		if columnIndex < len(row) {
+			if cellWidth := table.wrapperForCells.StringWidth(strings.TrimSpace(table.cellTextAsRendered(row[columnIndex]))); cellWidth > widestCell {
+				widestCell = cellWidth
+			}
+		}
+	}
+
+	return widestCell
+}
+
+func (table *Table) computeColumnWidths(numberOfColumns int, borders *tableBorders) ([]int, error) {
+	widthAvailableForCells := int(table.totalWidth) - borders.widthOfBordersFor(numberOfColumns)
+
+	columnWidths := make([]int, numberOfColumns)
+	minimumWidths := make([]int, numberOfColumns)
+	widthUsedByColumnsThatAreNotProportional := 0
+	minimumWidthOfProportionalColumns := 0
+
+	for columnIndex := range columnWidths {
+		column := table.columnAt(columnIndex)
+
+		switch {
+		case column.fixedWidth > 0:
+			columnWidths[columnIndex] = int(column.fixedWidth)
+			minimumWidths[columnIndex] = int(column.fixedWidth)
+
+		case column.proportionalWeight > 0:
+			columnWidths[columnIndex] = int(column.minimumWidth)
+			minimumWidths[columnIndex] = int(column.minimumWidth)
+			minimumWidthOfProportionalColumns += int(column.minimumWidth)
+			continue
+
+		default:
+			columnWidths[columnIndex] = table.widthOfWidestCellInColumn(columnIndex)
+			minimumWidths[columnIndex] = int(column.minimumWidth)
+
+			if columnWidths[columnIndex] < int(column.minimumWidth) {
+				columnWidths[columnIndex] = int(column.minimumWidth)
+			}
+
+			if column.maximumWidth > 0 && columnWidths[columnIndex] > int(column.maximumWidth) {
+				columnWidths[columnIndex] = int(column.maximumWidth)
+			}
+		}
+
+		widthUsedByColumnsThatAreNotProportional += columnWidths[columnIndex]
+	}
+
+	for widthUsedByColumnsThatAreNotProportional+minimumWidthOfProportionalColumns > widthAvailableForCells {
+		indexOfWidestShrinkableColumn := -1
+
+		for columnIndex, columnWidth := range columnWidths {
+			if table.columnAt(columnIndex).proportionalWeight == 0 && columnWidth > minimumWidths[columnIndex] {
+				if indexOfWidestShrinkableColumn < 0 || columnWidth > columnWidths[indexOfWidestShrinkableColumn] {
+					indexOfWidestShrinkableColumn = columnIndex
+				}
+			}
+		}
+
+		if indexOfWidestShrinkableColumn < 0 {
+			return nil, fmt.Errorf("table columns cannot fit in total width (%d)", table.totalWidth)
+		}
+
+		columnWidths[indexOfWidestShrinkableColumn]--
+		widthUsedByColumnsThatAreNotProportional--
+	}
+
+	table.distributeRemainingWidthToProportionalColumns(columnWidths, widthAvailableForCells-widthUsedByColumnsThatAreNotProportional-minimumWidthOfProportionalColumns)
+
+	return columnWidths, nil
+}
+
+func (table *Table) distributeRemainingWidthToProportionalColumns(columnWidths []int, remainingWidth int) {
+	for ; remainingWidth > 0; remainingWidth-- {
+		indexOfMostUnderfilledColumn := -1
+		var smallestWidthPerUnitOfWeight float64
+
+		for columnIndex, columnWidth := range columnWidths {
+			column := table.columnAt(columnIndex)
+			if column.fixedWidth > 0 || column.proportionalWeight == 0 || (column.maximumWidth > 0 && columnWidth >= int(column.maximumWidth)) {
+				continue
+			}
+
+			widthPerUnitOfWeight := float64(columnWidth) / float64(column.proportionalWeight)
+			if indexOfMostUnderfilledColumn < 0 || widthPerUnitOfWeight < smallestWidthPerUnitOfWeight {
+				indexOfMostUnderfilledColumn = columnIndex
+				smallestWidthPerUnitOfWeight = widthPerUnitOfWeight
+			}
+		}
+
+		if indexOfMostUnderfilledColumn < 0 {
+			return
+		}
+
+		columnWidths[indexOfMostUnderfilledColumn]++
+	}
+}
+
+func (table *Table) wrapCellText(cellText string, columnWidth int) ([]string, error) {
+	wrapperForCell := *table.wrapperForCells
+	wrapperForCell.detectIndentsFromInput = false
+	wrapperForCell.fontMetrics = nil
+	wrapperForCell.columnsPerRow = uint(columnWidth)
+
+	if columnWidth <= wrapperForCell.widthOfRunes(wrapperForCell.initialLineIndentString) || columnWidth <= wrapperForCell.widthOfRunes(wrapperForCell.subsequentLinesIndentString) {
+		wrapperForCell.initialLineIndentString = nil
+		wrapperForCell.subsequentLinesIndentString = nil
+	}
+
+	wrappedText, err := wrapperForCell.WrapStringText(table.cellTextAsRendered(cellText))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(wrappedText, wrapperForCell.lineBreakSequence), nil
+}
+
+func (table *Table) renderRowOfCells(cells []string, columnWidths []int, borders *tableBorders) ([]string, error) {
+	wrappedCells := make([][]string, len(columnWidths))
+	heightOfRow := 1
+
+	for columnIndex := range columnWidths {
+		if columnIndex < len(cells) {
+			wrappedCell, err := table.wrapCellText(cells[columnIndex], columnWidths[columnIndex])
+			if err != nil {
+				return nil, err
+			}
+
+			wrappedCells[columnIndex] = wrappedCell
+			if len(wrappedCell) > heightOfRow {
+				heightOfRow = len(wrappedCell)
+			}
+		}
+	}
+
+	renderedRows := make([]string, heightOfRow)
+
+	for rowIndex := range renderedRows {
+		var renderedRow strings.Builder
+		renderedRow.WriteString(borders.leftEdge)
+
+		for columnIndex, columnWidth := range columnWidths {
+			if columnIndex > 0 {
+				renderedRow.WriteString(borders.betweenCells)
+			}
+
+			cellRowText := ""
+			if rowIndex < len(wrappedCells[columnIndex]) {
+				cellRowText = wrappedCells[columnIndex][rowIndex]
+			}
+
+			renderedRow.WriteString(table.wrapperForCells.Pad(cellRowText, uint(columnWidth), table.columnAt(columnIndex).alignment))
+		}
+
+		renderedRow.WriteString(borders.rightEdge)
+
+		if table.borderStyle == NoTableBorders {
+			renderedRows[rowIndex] = strings.TrimRight(renderedRow.String(), " ")
+		} else {
+			renderedRows[rowIndex] = renderedRow.String()
+		}
+	}
+
+	return renderedRows, nil
+}
+
+func (table *Table) markdownAlignmentRule(columnWidths []int) string {
+	var alignmentRule strings.Builder
+	alignmentRule.WriteString("|")
+
+	for columnIndex, columnWidth := range columnWidths {
+		// some Markdown parsers need at least three characters in a delimiter cell, so in a column that is
+		// narrower than that, the delimiter cell fills the spaces around the cell as well
+		widthOfDelimiter, padding := columnWidth, " "
+		if columnWidth < 3 {
+			widthOfDelimiter, padding = columnWidth+2, ""
+			if widthOfDelimiter < 3 {
+				widthOfDelimiter = 3
+			}
+		}
+
+		dashes := []byte(strings.Repeat("-", widthOfDelimiter))
+
+		switch table.columnAt(columnIndex).alignment {
+		case AlignRight:
+			dashes[widthOfDelimiter-1] = ':'
+		case AlignCenter:
+			dashes[0] = ':'
+			dashes[widthOfDelimiter-1] = ':'
+		}
+
+		alignmentRule.WriteString(padding)
+		alignmentRule.Write(dashes)
+		alignmentRule.WriteString(padding)
+		alignmentRule.WriteString("|")
+	}
+
+	return alignmentRule.String()
+}
+
+type tableRuleCharacters struct {
+	left         string
+	horizontal   string
+	intersection string
+	right        string
+}
+
+type tableBorders struct {
+	hasOuterRules bool
+	leftEdge      string
+	betweenCells  string
+	rightEdge     string
+	topRule       tableRuleCharacters
+	middleRule    tableRuleCharacters
+	bottomRule    tableRuleCharacters
+}
+
+var tableBordersFor = map[TableBorderStyle]*tableBorders{
+	ASCIITableBorders: {
+		hasOuterRules: true,
+		leftEdge:      "| ",
+		betweenCells:  " | ",
+		rightEdge:     " |",
+		topRule:       tableRuleCharacters{"+", "-", "+", "+"},
+		middleRule:    tableRuleCharacters{"+", "-", "+", "+"},
+		bottomRule:    tableRuleCharacters{"+", "-", "+", "+"},
+	},
+	UnicodeBoxDrawingTableBorders: {
+		hasOuterRules: true,
+		leftEdge:      "│ ",
+		betweenCells:  " │ ",
+		rightEdge:     " │",
+		topRule:       tableRuleCharacters{"┌", "─", "┬", "┐"},
+		middleRule:    tableRuleCharacters{"├", "─", "┼", "┤"},
+		bottomRule:    tableRuleCharacters{"└", "─", "┴", "┘"},
+	},
+	MarkdownTableBorders: {
+		hasOuterRules: false,
+		leftEdge:      "| ",
+		betweenCells:  " | ",
+		rightEdge:     " |",
+	},
+	NoTableBorders: {
+		hasOuterRules: false,
+		leftEdge:      "",
+		betweenCells:  "  ",
+		rightEdge:     "",
+	},
+}
+
+func (borders *tableBorders) widthOfBordersFor(numberOfColumns int) int {
+	return StringWidth(borders.leftEdge) + (numberOfColumns-1)*StringWidth(borders.betweenCells) + StringWidth(borders.rightEdge)
+}
+
+func (borders *tableBorders) ruleUsing(ruleCharacters tableRuleCharacters, columnWidths []int) string {
+	var rule strings.Builder
+	rule.WriteString(ruleCharacters.left)
+
+	for columnIndex, columnWidth := range columnWidths {
+		if columnIndex > 0 {
+			rule.WriteString(ruleCharacters.intersection)
+		}
+
+		rule.WriteString(strings.Repeat(ruleCharacters.horizontal, columnWidth+2))
+	}
+
+	rule.WriteString(ruleCharacters.right)
+
+	return rule.String()
+}
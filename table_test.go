@@ -0,0 +1,166 @@
+package text_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type TableTestCase struct {
+	testName              string
+	table                 *text.Table
+	expectedRenderedTable string
+	expectAnError         bool
+}
+
+func (testCase *TableTestCase) RunTest() error {
+	renderedTable, err := testCase.table.Render()
+
+	if testCase.expectAnError {
+		if err == nil {
+			return fmt.Errorf("[%s] expected error, got none", testCase.testName)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("[%s] expected no error, got = (%s)", testCase.testName, err)
+	}
+
+	if renderedTable != testCase.expectedRenderedTable {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedRenderedTable, renderedTable)
+	}
+
+	return nil
+}
+
+func flagsTableUsingBorderStyle(style text.TableBorderStyle) *text.Table {
+	return text.NewTable().
+		UsingTotalWidth(40).
+		UsingBorderStyle(style).
+		UsingHeaderRow("Flag", "Description", "n").
+		UsingColumns(nil, nil, text.NewTableColumn().UsingAlignment(text.AlignRight)).
+		AppendRow("-o", "Write output to the named file instead of standard output", "1").
+		AppendRow("-v|x", "Verbose ∀∁∂∃", "22")
+}
+
+func TestTableRender(t *testing.T) {
+	testCases := []*TableTestCase{
+		{
+			testName: "ASCII borders",
+			table:    flagsTableUsingBorderStyle(text.ASCIITableBorders),
+			expectedRenderedTable: "" +
+				"+------+--------------------------+----+\n" +
+				"| Flag | Description              |  n |\n" +
+				"+------+--------------------------+----+\n" +
+				"| -o   | Write output to the      |  1 |\n" +
+				"|      | named file instead of    |    |\n" +
+				"|      | standard output          |    |\n" +
+				"| -v|x | Verbose ∀∁∂∃             | 22 |\n" +
+				"+------+--------------------------+----+",
+		},
+		{
+			testName: "Unicode box-drawing borders",
+			table:    flagsTableUsingBorderStyle(text.UnicodeBoxDrawingTableBorders),
+			expectedRenderedTable: "" +
+				"┌──────┬──────────────────────────┬────┐\n" +
+				"│ Flag │ Description              │  n │\n" +
+				"├──────┼──────────────────────────┼────┤\n" +
+				"│ -o   │ Write output to the      │  1 │\n" +
+				"│      │ named file instead of    │    │\n" +
+				"│      │ standard output          │    │\n" +
+				"│ -v|x │ Verbose ∀∁∂∃             │ 22 │\n" +
+				"└──────┴──────────────────────────┴────┘",
+		},
+		{
+			testName: "Markdown borders",
+			table:    flagsTableUsingBorderStyle(text.MarkdownTableBorders),
+			expectedRenderedTable: "" +
+				"| Flag  | Description             |  n |\n" +
+				"| ----- | ----------------------- |---:|\n" +
+				"| -o    | Write output to the     |  1 |\n" +
+				"|       | named file instead of   |    |\n" +
+				"|       | standard output         |    |\n" +
+				"| -v\\|x | Verbose ∀∁∂∃            | 22 |",
+		},
+		{
+			testName: "Markdown delimiters in one-character columns",
+			table: text.NewTable().
+				UsingTotalWidth(20).
+				UsingBorderStyle(text.MarkdownTableBorders).
+				UsingHeaderRow("a", "b", "c").
+				UsingColumns(
+					nil,
+					text.NewTableColumn().UsingAlignment(text.AlignCenter),
+					text.NewTableColumn().UsingAlignment(text.AlignRight)).
+				AppendRow("1", "2", "3"),
+			expectedRenderedTable: "" +
+				"| a | b | c |\n" +
+				"|---|:-:|--:|\n" +
+				"| 1 | 2 | 3 |",
+		},
+		{
+			testName: "no borders",
+			table:    flagsTableUsingBorderStyle(text.NoTableBorders),
+			expectedRenderedTable: "" +
+				"Flag  Description                      n\n" +
+				"-o    Write output to the named file   1\n" +
+				"      instead of standard output\n" +
+				"-v|x  Verbose ∀∁∂∃                    22",
+		},
+		{
+			testName: "fixed and proportional columns",
+			table: text.NewTable().
+				UsingTotalWidth(40).
+				UsingColumns(
+					text.NewTableColumn().UsingFixedWidth(5),
+					text.NewTableColumn().UsingProportionalWeight(2),
+					text.NewTableColumn().UsingProportionalWeight(1).UsingAlignment(text.AlignCenter)).
+				AppendRow("a", "b", "c").
+				AppendRow("abcdefgh", "b b b", "cc"),
+			expectedRenderedTable: "" +
+				"+-------+-------------------+----------+\n" +
+				"| a     | b                 |    c     |\n" +
+				"| abcde | b b b             |    cc    |\n" +
+				"| fgh   |                   |          |\n" +
+				"+-------+-------------------+----------+",
+		},
+		{
+			testName: "minimum and maximum widths",
+			table: text.NewTable().
+				UsingTotalWidth(30).
+				UsingColumns(
+					text.NewTableColumn().UsingMaximumWidth(6),
+					text.NewTableColumn().UsingMinimumWidth(12)).
+				AppendRow("one two three", "four five six seven eight nine"),
+			expectedRenderedTable: "" +
+				"+--------+-------------------+\n" +
+				"| one    | four five six     |\n" +
+				"| two    | seven eight nine  |\n" +
+				"| three  |                   |\n" +
+				"+--------+-------------------+",
+		},
+		{
+			testName:              "empty table",
+			table:                 text.NewTable(),
+			expectedRenderedTable: "",
+		},
+		{
+			testName:      "columns do not fit",
+			table:         text.NewTable().UsingTotalWidth(10).AppendRow("aaaa", "bbbb", "cccc"),
+			expectAnError: true,
+		},
+		{
+			testName:      "unknown border style",
+			table:         text.NewTable().UsingTotalWidth(40).UsingBorderStyle(text.TableBorderStyle(99)).AppendRow("aaaa", "bbbb"),
+			expectAnError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
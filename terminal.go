@@ -0,0 +1,77 @@
+package text
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalColumns returns the number of columns in the terminal attached to the process. On Linux, the size is
+// requested from the terminal device (using the TIOCGWINSZ ioctl) on standard output, standard error and then
+// standard input. If none of these is a terminal (or the platform is not Linux), the value of the COLUMNS
+// environment variable is used. If that is not set to a positive integer, found is false.
+func TerminalColumns() (columns uint, found bool) {
+	if columns, found := terminalColumnsFromTerminalDevice(); found {
+		return columns, true
+	}
+
+	if columns, err := strconv.ParseUint(os.Getenv("COLUMNS"), 10, 0); err == nil && columns > 0 {
+		return uint(columns), true
+	}
+
+	return 0, false
+}
+
+// rowWidthForTerminalColumns leaves the last terminal column empty (as the default row width of 79 does for an
+// 80 column terminal), so that a full row does not cause the terminal to advance the cursor to the next line
+// on its own. The row width is kept larger than the indent strings, because ChangeRowWidthTo() would panic otherwise.
+func (wrapper *Wrapper) rowWidthForTerminalColumns(columns uint) uint {
+	rowWidth := uint(79)
+	if columns > 1 {
+		rowWidth = columns - 1
+	}
+
+	for _, indent := range [][]rune{wrapper.initialLineIndentString, wrapper.subsequentLinesIndentString} {
+		if widthOfIndent := uint(widthOfRunesUsing(wrapper.columnAdvancer(), indent)); rowWidth <= widthOfIndent {
+			rowWidth = widthOfIndent + 1
+		}
+	}
+
+	return rowWidth
+}
+
+// NewWrapperSizedToTerminal is the same as NewWrapper(), but the row width is set from the size of the
+// terminal, as described for ChangeRowWidthToTerminalWidth().
+func NewWrapperSizedToTerminal() *Wrapper {
+	return NewWrapper().ChangeRowWidthToTerminalWidth()
+}
+
+// ChangeRowWidthToTerminalWidth changes the row width to one less than the number of columns returned by
+// TerminalColumns(). If the number of terminal columns cannot be found, the row width is set to the default
+// of 79. The row width is determined once, when this method is called. To follow changes to the terminal
+// size, use RewrapOnTerminalResize().
+func (wrapper *Wrapper) ChangeRowWidthToTerminalWidth() *Wrapper {
+	terminalColumns, _ := TerminalColumns()
+	return wrapper.ChangeRowWidthTo(wrapper.rowWidthForTerminalColumns(terminalColumns))
+}
+
+// UsingTerminalRowWidth is the same as ChangeRowWidthToTerminalWidth(), but provides a more readable name if this
+// is chained with the constructor, as in:
+//    wrapper := text.NewWrapper().UsingIndentStringForRowsAfterTheFirst("  ").UsingTerminalRowWidth()
+func (wrapper *Wrapper) UsingTerminalRowWidth() *Wrapper {
+	return wrapper.ChangeRowWidthToTerminalWidth()
+}
+
+// RewrapOnTerminalResize is intended for long-running interactive programs. Each time the terminal is resized
+// (that is, each time the process receives SIGWINCH), it calls rewrap with a copy of the Wrapper that has its
+// row width set for the new terminal size, as described for ChangeRowWidthToTerminalWidth(). The Wrapper itself
+// is not changed, so it is safe to continue using it while rewrap runs. rewrap is called from a separate goroutine,
+// one call at a time. Calling the returned function stops watching for resizes. On platforms other than Linux,
+// rewrap is never called.
+func (wrapper *Wrapper) RewrapOnTerminalResize(rewrap func(resizedWrapper *Wrapper)) (stopWatching func()) {
+	wrapperToResize := *wrapper
+
+	return watchForTerminalResize(func() {
+		resizedWrapper := wrapperToResize
+		rewrap(resizedWrapper.ChangeRowWidthToTerminalWidth())
+	})
+}
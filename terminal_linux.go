@@ -0,0 +1,57 @@
+//go:build linux
+// +build linux
+
+package text
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+type terminalWindowSize struct {
+	rows           uint16
+	columns        uint16
+	widthInPixels  uint16
+	heightInPixels uint16
+}
+
+func terminalColumnsFromTerminalDevice() (columns uint, found bool) {
+	for _, terminalFile := range []*os.File{os.Stdout, os.Stderr, os.Stdin} {
+		var windowSize terminalWindowSize
+
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, terminalFile.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&windowSize)))
+		if errno == 0 && windowSize.columns > 0 {
+			return uint(windowSize.columns), true
+		}
+	}
+
+	return 0, false
+}
+
+func watchForTerminalResize(onResize func()) (stopWatching func()) {
+	resizeSignals := make(chan os.Signal, 1)
+	stopSignal := make(chan struct{})
+	signal.Notify(resizeSignals, syscall.SIGWINCH)
+
+	go func() {
+		for {
+			select {
+			case <-resizeSignals:
+				onResize()
+			case <-stopSignal:
+				return
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			signal.Stop(resizeSignals)
+			close(stopSignal)
+		})
+	}
+}
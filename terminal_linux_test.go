@@ -0,0 +1,57 @@
+//go:build linux
+// +build linux
+
+package text_test
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/text"
+)
+
+func TestRewrapOnTerminalResize(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+
+	for _, testCase := range []struct {
+		testName string
+		wrapper  *text.Wrapper
+	}{
+		{testName: "columns", wrapper: text.NewWrapper().UsingRowWidth(20)},
+		{testName: "font metrics", wrapper: text.NewWrapper().UsingFontMetrics(text.NewFontMetricsTable(1), 20)},
+	} {
+		resizedWrappers := make(chan *text.Wrapper, 1)
+		wrapper := testCase.wrapper
+
+		stopWatching := wrapper.RewrapOnTerminalResize(func(resizedWrapper *text.Wrapper) {
+			resizedWrappers <- resizedWrapper
+		})
+
+		if err := syscall.Kill(os.Getpid(), syscall.SIGWINCH); err != nil {
+			stopWatching()
+			t.Fatalf("[%s] failed to send SIGWINCH: %s", testCase.testName, err)
+		}
+
+		select {
+		case resizedWrapper := <-resizedWrappers:
+			terminalColumns, _ := text.TerminalColumns()
+			firstRow := strings.Split(resizedWrapper.MustWrapStringText(strings.Repeat("x", 500)), "\n")[0]
+			if len(firstRow) != int(terminalColumns)-1 {
+				t.Errorf("[%s] expected resized first row length = (%d), got = (%d)", testCase.testName, terminalColumns-1, len(firstRow))
+			}
+
+		case <-time.After(5 * time.Second):
+			stopWatching()
+			t.Fatalf("[%s] rewrap was not called after SIGWINCH", testCase.testName)
+		}
+
+		stopWatching()
+
+		if firstRow := strings.Split(wrapper.MustWrapStringText(strings.Repeat("x", 500)), "\n")[0]; len(firstRow) != 20 {
+			t.Errorf("[%s] expected original wrapper first row length = (20), got = (%d)", testCase.testName, len(firstRow))
+		}
+	}
+}
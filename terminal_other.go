@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package text
+
+func terminalColumnsFromTerminalDevice() (columns uint, found bool) {
+	return 0, false
+}
+
+func watchForTerminalResize(onResize func()) (stopWatching func()) {
+	return func() {}
+}
@@ -0,0 +1,33 @@
+package text_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+func TestTerminalColumnsFromEnvironment(t *testing.T) {
+	t.Setenv("COLUMNS", "120")
+
+	terminalColumns, found := text.TerminalColumns()
+	if !found {
+		t.Fatalf("expected terminal columns to be found when COLUMNS is set")
+	}
+
+	wrappedText := text.NewWrapperSizedToTerminal().MustWrapStringText(strings.Repeat("x", 500))
+	firstRow := strings.Split(wrappedText, "\n")[0]
+
+	if len(firstRow) != int(terminalColumns)-1 {
+		t.Errorf("expected first row length = (%d), got = (%d)", terminalColumns-1, len(firstRow))
+	}
+}
+
+func TestTerminalRowWidthLeavesRoomForIndent(t *testing.T) {
+	t.Setenv("COLUMNS", "1")
+
+	wrapper := text.NewWrapper().UsingIndentStringForRowsAfterTheFirst("    ").UsingTerminalRowWidth()
+	if _, err := wrapper.WrapStringText("a b c"); err != nil {
+		t.Errorf("expected no error, got = (%s)", err)
+	}
+}
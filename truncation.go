@@ -0,0 +1,320 @@
+package text
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// TruncationPosition determines which part of the wrapped text is dropped when it has more rows than the
+// maximum set by ChangeMaximumRowsTo().
+type TruncationPosition int
+
+const (
+	// TruncateEnd keeps the first rows. The ellipsis is placed at the end of the last row that is kept.
+	TruncateEnd TruncationPosition = iota
+
+	// TruncateStart keeps the last rows. The ellipsis is placed at the start of the first row that is kept,
+	// and that row uses the indent for the first row.
+	TruncateStart
+
+	// TruncateMiddle keeps the first rows and the last rows, dropping the rows in between. The last row kept
+	// before the dropped rows is shortened to make room for the ellipsis and the end of the last dropped row.
+	// With a maximum of one row, this shortens the text in the same way file paths are often shortened, as in
+	// "/usr/share/…/README.md".
+	TruncateMiddle
+)
+
+// ChangeMaximumRowsTo sets the maximum number of rows in the wrapped text. If the text wraps into more rows than
+// this, rows are dropped (see ChangeTruncationPositionTo()) and an ellipsis is inserted to show that text is missing.
+// When the ellipsis is placed at the end or the start of a row, words are removed from that row until the ellipsis
+// fits. If the row has only one word, characters are removed instead, but a character is never separated from the
+// combining marks that follow it. When the position is TruncateMiddle, the row is shortened by characters rather than
+// by words. A value of 0 (the default) means there is no maximum.
+func (wrapper *Wrapper) ChangeMaximumRowsTo(numberOfRows uint) *Wrapper {
+	wrapper.maximumRows = numberOfRows
+	return wrapper
+}
+
+// UsingMaximumRows is the same as ChangeMaximumRowsTo(), but provides a more readable name if this is chained with
+// the constructor.
+func (wrapper *Wrapper) UsingMaximumRows(numberOfRows uint) *Wrapper {
+	return wrapper.ChangeMaximumRowsTo(numberOfRows)
+}
+
+// ChangeEllipsisStringTo sets the string that is inserted where text is dropped when the wrapped text has more rows
+// than the maximum. The default is "…" (U+2026). The ellipsis counts against the row column count.
+func (wrapper *Wrapper) ChangeEllipsisStringTo(ellipsis string) *Wrapper {
+	wrapper.ellipsisString = []rune(ellipsis)
+	return wrapper
+}
+
+// UsingEllipsisString is the same as ChangeEllipsisStringTo(), but provides a more readable name if this is chained
+// with the constructor.
+func (wrapper *Wrapper) UsingEllipsisString(ellipsis string) *Wrapper {
+	return wrapper.ChangeEllipsisStringTo(ellipsis)
+}
+
+// ChangeTruncationPositionTo sets which rows are dropped when the wrapped text has more rows than the maximum. The
+// default is TruncateEnd.
+func (wrapper *Wrapper) ChangeTruncationPositionTo(position TruncationPosition) *Wrapper {
+	wrapper.truncationPosition = position
+	return wrapper
+}
+
+// UsingTruncationPosition is the same as ChangeTruncationPositionTo(), but provides a more readable name if this is
+// chained with the constructor.
+func (wrapper *Wrapper) UsingTruncationPosition(position TruncationPosition) *Wrapper {
+	return wrapper.ChangeTruncationPositionTo(position)
+}
+
+func (wrapper *Wrapper) wrapFromReaderAndTruncate(reader io.Reader) (wrappedText string, contentWasDropped bool, err error) {
+	wrappedText, err = wrapper.wrapFromReader(reader)
+	return wrapper.truncatedAndLaidOutForBidi(wrappedText, err)
+}
+
+func (wrapper *Wrapper) wrapBytesAndTruncate(unwrappedBytes []byte) (wrappedText string, contentWasDropped bool, err error) {
+	var bufferOfWrappedText bytes.Buffer
+	bufferOfWrappedText.Grow(len(unwrappedBytes))
+
+	err = wrapper.wrapBytesInto(&bufferOfWrappedText, unwrappedBytes)
+	return wrapper.truncatedAndLaidOutForBidi(bufferOfWrappedText.String(), err)
+}
+
+// truncatedAndLaidOutForBidi applies the maximum rows and the bidi mode to wrapped text, unless wrapping failed with
+// err.
+func (wrapper *Wrapper) truncatedAndLaidOutForBidi(wrappedText string, err error) (string, bool, error) {
+	if err != nil {
+		return wrappedText, false, err
+	}
+
+	wrappedText, contentWasDropped := wrapper.truncatedToMaximumRows(wrappedText)
+
+	wrappedText, err = wrapper.laidOutForBidi(wrappedText)
+	if err != nil {
+		return "", false, err
+	}
+
+	return wrappedText, contentWasDropped, nil
+}
+
+func (wrapper *Wrapper) truncatedToMaximumRows(wrappedText string) (truncatedText string, contentWasDropped bool) {
+	if wrapper.maximumRows == 0 || wrappedText == "" {
+		return wrappedText, false
+	}
+
+	wrappedRows := strings.Split(wrappedText, wrapper.lineBreakSequence)
+	if uint(len(wrappedRows)) <= wrapper.maximumRows {
+		return wrappedText, false
+	}
+
+	maximumRows := int(wrapper.maximumRows)
+	keptRows := make([]string, 0, maximumRows)
+
+	switch wrapper.truncationPosition {
+	case TruncateStart:
+		firstKeptRow := []rune(wrappedRows[len(wrappedRows)-maximumRows])
+		keptRows = append(keptRows, wrapper.rowWithEllipsisAtStart(firstKeptRow[len(wrapper.subsequentLinesIndentString):]))
+		keptRows = append(keptRows, wrappedRows[len(wrappedRows)-maximumRows+1:]...)
+
+	case TruncateMiddle:
+		numberOfRowsKeptBeforeEllipsis := (maximumRows + 1) / 2
+		numberOfRowsKeptAfterEllipsis := maximumRows - numberOfRowsKeptBeforeEllipsis
+		indexOfRowWithEllipsis := numberOfRowsKeptBeforeEllipsis - 1
+		indexOfLastDroppedRow := len(wrappedRows) - numberOfRowsKeptAfterEllipsis - 1
+
+		keptRows = append(keptRows, wrappedRows[:indexOfRowWithEllipsis]...)
+		keptRows = append(keptRows, wrapper.rowWithEllipsisInTheMiddle(
+			[]rune(wrappedRows[indexOfRowWithEllipsis]),
+			wrapper.indentStringForRow(indexOfRowWithEllipsis),
+			[]rune(wrappedRows[indexOfLastDroppedRow])[len(wrapper.subsequentLinesIndentString):]))
+		keptRows = append(keptRows, wrappedRows[indexOfLastDroppedRow+1:]...)
+
+	default:
+		keptRows = append(keptRows, wrappedRows[:maximumRows-1]...)
+		keptRows = append(keptRows, wrapper.rowWithEllipsisAtEnd([]rune(wrappedRows[maximumRows-1]), wrapper.indentStringForRow(maximumRows-1)))
+	}
+
+	return strings.Join(keptRows, wrapper.lineBreakSequence), true
+}
+
+func (wrapper *Wrapper) indentStringForRow(rowIndex int) []rune {
+	if rowIndex == 0 {
+		return wrapper.initialLineIndentString
+	}
+
+	return wrapper.subsequentLinesIndentString
+}
+
+func (wrapper *Wrapper) columnsAvailableForTextAndEllipsisAfter(indent []rune) int {
+	return int(wrapper.rowWidthInWidthUnits()) - wrapper.widthOfRunes(indent)
+}
+
+func (wrapper *Wrapper) ellipsisAfterIndentWhenNothingElseFits(indent []rune) string {
+	columnsAvailableForEllipsis := wrapper.columnsAvailableForTextAndEllipsisAfter(indent)
+	return string(indent) + string(runesBeforeCharacterAt(wrapper.advancer(), wrapper.ellipsisString, columnsAvailableForEllipsis))
+}
+
+func (wrapper *Wrapper) rowWithEllipsisAtEnd(row []rune, indent []rune) string {
+	rowText := trimRightSpaceFromRunes(row[len(indent):])
+	columnsAvailableForText := wrapper.columnsAvailableForTextAndEllipsisAfter(indent) - wrapper.widthOfRunes(wrapper.ellipsisString)
+
+	if columnsAvailableForText <= 0 {
+		return wrapper.ellipsisAfterIndentWhenNothingElseFits(indent)
+	}
+
+	if wrapper.widthOfRunes(rowText) > columnsAvailableForText {
+		rowText = trimRightSpaceFromRunes(runesBeforeWordBoundaryOrCharacterAt(wrapper.advancer(), rowText, columnsAvailableForText))
+	}
+
+	return string(indent) + string(rowText) + string(wrapper.ellipsisString)
+}
+
+func (wrapper *Wrapper) rowWithEllipsisAtStart(rowText []rune) string {
+	indent := wrapper.initialLineIndentString
+	rowText = trimLeftSpaceFromRunes(rowText)
+	columnsAvailableForText := wrapper.columnsAvailableForTextAndEllipsisAfter(indent) - wrapper.widthOfRunes(wrapper.ellipsisString)
+
+	if columnsAvailableForText <= 0 {
+		return wrapper.ellipsisAfterIndentWhenNothingElseFits(indent)
+	}
+
+	if wrapper.widthOfRunes(rowText) > columnsAvailableForText {
+		rowText = trimLeftSpaceFromRunes(runesAfterWordBoundaryOrCharacterAt(wrapper.advancer(), rowText, columnsAvailableForText))
+	}
+
+	return string(indent) + string(wrapper.ellipsisString) + string(rowText)
+}
+
+func (wrapper *Wrapper) rowWithEllipsisInTheMiddle(row []rune, indent []rune, lastDroppedRowText []rune) string {
+	columnsAvailableForText := wrapper.columnsAvailableForTextAndEllipsisAfter(indent) - wrapper.widthOfRunes(wrapper.ellipsisString)
+
+	if columnsAvailableForText <= 0 {
+		return wrapper.ellipsisAfterIndentWhenNothingElseFits(indent)
+	}
+
+	textBeforeEllipsis := trimRightSpaceFromRunes(row[len(indent):])
+	textAfterEllipsis := trimLeftSpaceFromRunes(lastDroppedRowText)
+
+	columnsBeforeEllipsis := (columnsAvailableForText + 1) / 2
+	if widthOfTextBeforeEllipsis := wrapper.widthOfRunes(textBeforeEllipsis); columnsBeforeEllipsis > widthOfTextBeforeEllipsis {
+		columnsBeforeEllipsis = widthOfTextBeforeEllipsis
+	}
+
+	columnsAfterEllipsis := columnsAvailableForText - columnsBeforeEllipsis
+
+	textBeforeEllipsis = trimRightSpaceFromRunes(runesBeforeCharacterAt(wrapper.advancer(), textBeforeEllipsis, columnsBeforeEllipsis))
+	textAfterEllipsis = trimLeftSpaceFromRunes(runesAfterCharacterAt(wrapper.advancer(), textAfterEllipsis, columnsAfterEllipsis))
+
+	return string(indent) + string(textBeforeEllipsis) + string(wrapper.ellipsisString) + string(textAfterEllipsis)
+}
+
+// runeExtendsPreviousCharacter returns true for runes that are displayed as part of the character before them
+// (combining marks, variation selectors and the zero width joiner), so that truncation does not separate them.
+func runeExtendsPreviousCharacter(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector) || r == '\u200d'
+}
+
+// indexOfFirstRuneAfterColumns returns the number of runes from the start of runes that fit in maxColumns.
+// Runes with zero width that follow the last rune that fits are counted as fitting.
+func indexOfFirstRuneAfterColumns(advancer runeAdvancer, runes []rune, maxColumns int) int {
+	columnsUsed := 0
+	previousRune := noPreviousRune
+
+	for runeIndex, r := range runes {
+		columnsUsed += advancer.advanceOf(previousRune, r)
+		if columnsUsed > maxColumns {
+			return runeIndex
+		}
+
+		previousRune = r
+	}
+
+	return len(runes)
+}
+
+// indexOfFirstRuneInLastColumns returns the index of the first rune in the longest run at the end of runes that
+// fits in maxColumns.
+func indexOfFirstRuneInLastColumns(advancer runeAdvancer, runes []rune, maxColumns int) int {
+	columnsUsed := 0
+	for runeIndex := len(runes) - 1; runeIndex >= 0; runeIndex-- {
+		columnsUsed += advancer.advanceOf(noPreviousRune, runes[runeIndex])
+		if runeIndex+1 < len(runes) {
+			// the rune that was first is now kerned with the rune before it
+			columnsUsed += advancer.advanceOf(runes[runeIndex], runes[runeIndex+1]) - advancer.advanceOf(noPreviousRune, runes[runeIndex+1])
+		}
+
+		if columnsUsed > maxColumns {
+			return runeIndex + 1
+		}
+	}
+
+	return 0
+}
+
+// runesBeforeCharacterAt returns the longest run from the start of runes that is at most maxColumns wide, stopping
+// earlier if needed so that a character and its combining marks are not separated.
+func runesBeforeCharacterAt(advancer runeAdvancer, runes []rune, maxColumns int) []rune {
+	cutIndex := indexOfFirstRuneAfterColumns(advancer, runes, maxColumns)
+	for cutIndex > 0 && cutIndex < len(runes) && runeExtendsPreviousCharacter(runes[cutIndex]) {
+		cutIndex--
+	}
+
+	return runes[:cutIndex]
+}
+
+// runesAfterCharacterAt returns the longest run at the end of runes that is at most maxColumns wide, starting later
+// if needed so that a character and its combining marks are not separated.
+func runesAfterCharacterAt(advancer runeAdvancer, runes []rune, maxColumns int) []rune {
+	startIndex := indexOfFirstRuneInLastColumns(advancer, runes, maxColumns)
+	for startIndex < len(runes) && runeExtendsPreviousCharacter(runes[startIndex]) {
+		startIndex++
+	}
+
+	return runes[startIndex:]
+}
+
+// runesBeforeWordBoundaryOrCharacterAt returns the longest run of whole words from the start of runes that is no
+// more than maxColumns wide. If even the first word is wider than that, it returns runesBeforeCharacterAt().
+func runesBeforeWordBoundaryOrCharacterAt(advancer runeAdvancer, runes []rune, maxColumns int) []rune {
+	for cutIndex := indexOfFirstRuneAfterColumns(advancer, runes, maxColumns); cutIndex > 0; cutIndex-- {
+		if cutIndex < len(runes) && unicode.IsSpace(runes[cutIndex]) {
+			if wholeWords := trimRightSpaceFromRunes(runes[:cutIndex]); len(wholeWords) > 0 {
+				return wholeWords
+			}
+		}
+	}
+
+	return runesBeforeCharacterAt(advancer, runes, maxColumns)
+}
+
+// runesAfterWordBoundaryOrCharacterAt returns the longest run of whole words at the end of runes that is no more
+// than maxColumns wide. If even the last word is wider than that, it returns runesAfterCharacterAt().
+func runesAfterWordBoundaryOrCharacterAt(advancer runeAdvancer, runes []rune, maxColumns int) []rune {
+	for cutIndex := indexOfFirstRuneInLastColumns(advancer, runes, maxColumns) - 1; cutIndex < len(runes); cutIndex++ {
+		if cutIndex >= 0 && unicode.IsSpace(runes[cutIndex]) {
+			if wholeWords := trimLeftSpaceFromRunes(runes[cutIndex:]); len(wholeWords) > 0 {
+				return wholeWords
+			}
+		}
+	}
+
+	return runesAfterCharacterAt(advancer, runes, maxColumns)
+}
+
+func trimRightSpaceFromRunes(runes []rune) []rune {
+	for len(runes) > 0 && unicode.IsSpace(runes[len(runes)-1]) {
+		runes = runes[:len(runes)-1]
+	}
+
+	return runes
+}
+
+func trimLeftSpaceFromRunes(runes []rune) []rune {
+	for len(runes) > 0 && unicode.IsSpace(runes[0]) {
+		runes = runes[1:]
+	}
+
+	return runes
+}
@@ -0,0 +1,150 @@
+package text_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type TruncationTestCase struct {
+	testName                  string
+	wrapper                   *text.Wrapper
+	unwrappedString           string
+	expectedWrappedString     string
+	expectedContentWasDropped bool
+}
+
+func (testCase *TruncationTestCase) RunTest() error {
+	wrappedString, contentWasDropped, err := testCase.wrapper.WrapStringTextAndReportTruncation(testCase.unwrappedString)
+	if err != nil {
+		return fmt.Errorf("[%s] on WrapStringTextAndReportTruncation(), got error = (%s)", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] on WrapStringTextAndReportTruncation(), expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	if contentWasDropped != testCase.expectedContentWasDropped {
+		return fmt.Errorf("[%s] expected contentWasDropped = (%t), got = (%t)", testCase.testName, testCase.expectedContentWasDropped, contentWasDropped)
+	}
+
+	wrappedString, contentWasDropped, err = testCase.wrapper.WrapUTF8TextFromAReaderAndReportTruncation(strings.NewReader(testCase.unwrappedString))
+	if err != nil {
+		return fmt.Errorf("[%s] on WrapUTF8TextFromAReaderAndReportTruncation(), got error = (%s)", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString || contentWasDropped != testCase.expectedContentWasDropped {
+		return fmt.Errorf("[%s] on WrapUTF8TextFromAReaderAndReportTruncation(), expected = (%q, %t), got = (%q, %t)", testCase.testName, testCase.expectedWrappedString, testCase.expectedContentWasDropped, wrappedString, contentWasDropped)
+	}
+
+	return nil
+}
+
+var unwrappedTruncationString01 string = "The quick brown fox jumps over the lazy dog and then keeps running far away into the distance."
+
+func indentedWrapperWithMaximumRows(maximumRows uint, position text.TruncationPosition) *text.Wrapper {
+	return text.NewWrapper().
+		UsingRowWidth(20).
+		UsingIndentStringForFirstRow("> ").
+		UsingIndentStringForRowsAfterTheFirst("  ").
+		UsingMaximumRows(maximumRows).
+		UsingTruncationPosition(position)
+}
+
+func TestTruncation(t *testing.T) {
+	testCases := []*TruncationTestCase{
+		{
+			testName:                  "truncate end, one row",
+			wrapper:                   indentedWrapperWithMaximumRows(1, text.TruncateEnd),
+			unwrappedString:           unwrappedTruncationString01,
+			expectedWrappedString:     "> The quick brown…",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:        "truncate end, three rows",
+			wrapper:         indentedWrapperWithMaximumRows(3, text.TruncateEnd),
+			unwrappedString: unwrappedTruncationString01,
+			expectedWrappedString: "" +
+				"> The quick brown\n" +
+				"  fox jumps over the\n" +
+				"  lazy dog and then…",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:        "truncate end, fewer rows than maximum",
+			wrapper:         indentedWrapperWithMaximumRows(10, text.TruncateEnd),
+			unwrappedString: unwrappedTruncationString01,
+			expectedWrappedString: "" +
+				"> The quick brown\n" +
+				"  fox jumps over the\n" +
+				"  lazy dog and then\n" +
+				"  keeps running far\n" +
+				"  away into the\n" +
+				"  distance.",
+			expectedContentWasDropped: false,
+		},
+		{
+			testName:                  "truncate start, one row",
+			wrapper:                   indentedWrapperWithMaximumRows(1, text.TruncateStart),
+			unwrappedString:           unwrappedTruncationString01,
+			expectedWrappedString:     "> …distance.",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:        "truncate start, three rows",
+			wrapper:         indentedWrapperWithMaximumRows(3, text.TruncateStart),
+			unwrappedString: unwrappedTruncationString01,
+			expectedWrappedString: "" +
+				"> …keeps running far\n" +
+				"  away into the\n" +
+				"  distance.",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:                  "truncate middle, one row",
+			wrapper:                   indentedWrapperWithMaximumRows(1, text.TruncateMiddle),
+			unwrappedString:           unwrappedTruncationString01,
+			expectedWrappedString:     "> The quick…istance.",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:        "truncate middle, three rows",
+			wrapper:         indentedWrapperWithMaximumRows(3, text.TruncateMiddle),
+			unwrappedString: unwrappedTruncationString01,
+			expectedWrappedString: "" +
+				"> The quick brown\n" +
+				"  fox jumps…into the\n" +
+				"  distance.",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:                  "truncate middle of a path with a custom ellipsis",
+			wrapper:                   text.NewWrapper().UsingRowWidth(24).UsingMaximumRows(1).UsingTruncationPosition(text.TruncateMiddle).UsingEllipsisString("..."),
+			unwrappedString:           "/usr/share/doc/some-package/examples/README.md",
+			expectedWrappedString:     "/usr/share/.../README.md",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:                  "truncate a single word without separating a combining mark",
+			wrapper:                   text.NewWrapper().UsingRowWidth(10).UsingMaximumRows(1),
+			unwrappedString:           "abcdefghe\u0301xyz",
+			expectedWrappedString:     "abcdefgh…",
+			expectedContentWasDropped: true,
+		},
+		{
+			testName:                  "ellipsis wider than the row",
+			wrapper:                   text.NewWrapper().UsingRowWidth(4).UsingIndentStringForFirstRow("> ").UsingMaximumRows(1).UsingEllipsisString("[more]"),
+			unwrappedString:           "one two three",
+			expectedWrappedString:     "> [m",
+			expectedContentWasDropped: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
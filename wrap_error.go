@@ -0,0 +1,38 @@
+package text
+
+import "fmt"
+
+// WrapError is returned when wrapping stops before the end of the input, because reading the input failed or
+// because of an invalid byte (see ChangeInvalidUTF8PolicyTo()). It wraps the error that stopped wrapping, which can
+// be tested using errors.Is() and errors.As(). The wrapped text returned with a WrapError has every character
+// before the position reported in the WrapError, so wrapping can be resumed by wrapping the input that starts at
+// ByteOffset.
+//
+// Positions are in the UTF-8 text being wrapped (that is, after any decoding from another encoding and any
+// normalization, and, if indents are detected from the input, after the whitespace around each line is removed).
+type WrapError struct {
+	// Err is the error that stopped wrapping.
+	Err error
+
+	// ByteOffset is the offset, in bytes from the start of the input, of the first character that is not in the
+	// returned wrapped text.
+	ByteOffset int64
+
+	// RuneOffset is the same position as ByteOffset, counted in runes (each invalid byte counts as one rune).
+	RuneOffset int64
+
+	// Line is the line of the input (starting at 1) that contains that character. Lines end with a line feed.
+	Line int
+
+	// Row is the row of the wrapped text (starting at 1) in which wrapping stopped.
+	Row int
+}
+
+func (err *WrapError) Error() string {
+	return fmt.Sprintf("wrapping stopped at line %d (byte offset %d, rune offset %d), output row %d: %s", err.Line, err.ByteOffset, err.RuneOffset, err.Row, err.Err)
+}
+
+// Unwrap returns the error that stopped wrapping.
+func (err *WrapError) Unwrap() error {
+	return err.Err
+}
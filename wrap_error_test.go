@@ -0,0 +1,117 @@
+package text_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/blorticus-go/text"
+)
+
+var errReaderFailed = errors.New("reader failed")
+
+type WrapErrorTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	reader                io.Reader
+	expectedWrappedString string
+	expectedCause         error
+	expectedByteOffset    int64
+	expectedRuneOffset    int64
+	expectedLine          int
+	expectedRow           int
+}
+
+func (testCase *WrapErrorTestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapUTF8TextFromAReader(testCase.reader)
+
+	var wrapError *text.WrapError
+	if !errors.As(err, &wrapError) {
+		return fmt.Errorf("[%s] expected a WrapError, got = (%v)", testCase.testName, err)
+	}
+
+	if testCase.expectedCause != nil && !errors.Is(err, testCase.expectedCause) {
+		return fmt.Errorf("[%s] expected error to wrap (%v), got = (%v)", testCase.testName, testCase.expectedCause, err)
+	}
+
+	if wrapError.ByteOffset != testCase.expectedByteOffset || wrapError.RuneOffset != testCase.expectedRuneOffset || wrapError.Line != testCase.expectedLine || wrapError.Row != testCase.expectedRow {
+		return fmt.Errorf("[%s] expected byte offset = %d, rune offset = %d, line = %d, row = %d; got byte offset = %d, rune offset = %d, line = %d, row = %d",
+			testCase.testName, testCase.expectedByteOffset, testCase.expectedRuneOffset, testCase.expectedLine, testCase.expectedRow,
+			wrapError.ByteOffset, wrapError.RuneOffset, wrapError.Line, wrapError.Row)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+func TestWrapError(t *testing.T) {
+	testCases := []*WrapErrorTestCase{
+		{
+			testName:              "reader error",
+			wrapper:               text.NewWrapper().UsingRowWidth(9),
+			reader:                io.MultiReader(strings.NewReader("one two\nthree four fi"), iotest.ErrReader(errReaderFailed)),
+			expectedWrappedString: "one two\nthree\nfour",
+			expectedCause:         errReaderFailed,
+			expectedByteOffset:    18,
+			expectedRuneOffset:    18,
+			expectedLine:          2,
+			expectedRow:           3,
+		},
+		{
+			testName:              "reader error before any text is wrapped",
+			wrapper:               text.NewWrapper(),
+			reader:                io.MultiReader(strings.NewReader("  one"), iotest.ErrReader(errReaderFailed)),
+			expectedWrappedString: "",
+			expectedCause:         errReaderFailed,
+			expectedByteOffset:    0,
+			expectedRuneOffset:    0,
+			expectedLine:          1,
+			expectedRow:           1,
+		},
+		{
+			testName:              "invalid byte after multi-byte characters",
+			wrapper:               text.NewWrapper(),
+			reader:                strings.NewReader("日本\n\n語\xff"),
+			expectedWrappedString: "日本",
+			expectedByteOffset:    6,
+			expectedRuneOffset:    2,
+			expectedLine:          1,
+			expectedRow:           1,
+		},
+		{
+			testName:              "invalid byte in a word that was started on a new row",
+			wrapper:               text.NewWrapper().UsingRowWidth(5).UsingWordSegmenter(text.NewDictionaryWordSegmenter()),
+			reader:                strings.NewReader("ภาษาไทย ง่าย\xff"),
+			expectedWrappedString: "ภาษา\nไทย\nง",
+			expectedByteOffset:    25,
+			expectedRuneOffset:    9,
+			expectedLine:          1,
+			expectedRow:           3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := testCase.RunTest(); err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestWrapErrorWrapsInvalidUTF8Error(t *testing.T) {
+	_, err := text.NewWrapper().WrapStringText("日本 語\xff")
+
+	var invalidUTF8Error *text.InvalidUTF8Error
+	if !errors.As(err, &invalidUTF8Error) {
+		t.Fatalf("expected an InvalidUTF8Error, got = (%v)", err)
+	}
+
+	if invalidUTF8Error.ByteOffset != 10 {
+		t.Errorf("expected the invalid byte at offset 10, got = %d", invalidUTF8Error.ByteOffset)
+	}
+}
@@ -4,6 +4,7 @@ package text
 import (
 	"bytes"
 	"io"
+	"strings"
 	"unicode"
 
 	"github.com/blorticus-go/nibblers"
@@ -24,28 +25,72 @@ import (
 // The characters in the preamble count against the row column count. A configurable preamble may also be be inserted
 // on the initial line, but it is configured separately from the subsequent line indents in case the two should
 // be different (a common case is to have no initial indent, but have a fixed number of spaces on subsequent lines).
+// A Wrapper may be used by many goroutines at once, as long as it is not changed while they use it.
 type Wrapper struct {
-	columnsPerRow               uint
-	initialLineIndentString     []rune
-	subsequentLinesIndentString []rune
-	lineBreakSequence           string
-	nibblerMatcher              *nibblers.UTF8NibblerMatcher
-	nibbler                     nibblers.UTF8Nibbler
+	columnsPerRow                 uint
+	initialLineIndentString       []rune
+	subsequentLinesIndentString   []rune
+	lineBreakSequence             string
+	detectIndentsFromInput        bool
+	maximumRows                   uint
+	ellipsisString                []rune
+	truncationPosition            TruncationPosition
+	measurer                      Measurer
+	fontMetrics                   FontMetrics
+	fontRowWidth                  uint
+	bidiMode                      BidiMode
+	paragraphDirection            ParagraphDirection
+	rightToLeftAlignment          Alignment
+	wordSegmenter                 WordSegmenter
+	kinsokuAdjustment             KinsokuAdjustment
+	kinsokuProhibitedAtStartOfRow map[rune]bool
+	kinsokuProhibitedAtEndOfRow   map[rune]bool
+	inputNormalization            InputNormalization
+	detectByteOrderMark           bool
+	invalidUTF8Policy             InvalidUTF8Policy
+	inputByteLimit                uint
+	outputRowLimit                uint
+	wordLengthLimit               uint
 }
 
 // NewWrapper creates an empty wrapper.
 func NewWrapper() *Wrapper {
 	return &Wrapper{
-		columnsPerRow:               79,
-		initialLineIndentString:     nil,
-		subsequentLinesIndentString: nil,
-		lineBreakSequence:           "\n",
+		columnsPerRow:                 79,
+		initialLineIndentString:       nil,
+		subsequentLinesIndentString:   nil,
+		lineBreakSequence:             "\n",
+		detectIndentsFromInput:        false,
+		maximumRows:                   0,
+		ellipsisString:                []rune("…"),
+		truncationPosition:            TruncateEnd,
+		measurer:                      RuneCountMeasurer,
+		fontMetrics:                   nil,
+		fontRowWidth:                  0,
+		bidiMode:                      BidiDisabled,
+		paragraphDirection:            DetectParagraphDirection,
+		rightToLeftAlignment:          AlignRight,
+		wordSegmenter:                 nil,
+		kinsokuAdjustment:             KinsokuDisabled,
+		kinsokuProhibitedAtStartOfRow: defaultKinsokuProhibitedAtStartOfRow,
+		kinsokuProhibitedAtEndOfRow:   defaultKinsokuProhibitedAtEndOfRow,
+		inputNormalization:            NoNormalization,
+		detectByteOrderMark:           false,
+		invalidUTF8Policy:             FailOnInvalidUTF8,
+		inputByteLimit:                0,
+		outputRowLimit:                0,
+		wordLengthLimit:               0,
 	}
 }
 
-// ChangeRowWidthTo changes the column width to the provided value. The default column width is 79.
+// ChangeRowWidthTo changes the column width to the provided value. The default column width is 79. If the Wrapper
+// has FontMetrics (see ChangeFontMetricsTo()), the value is a number of points.
 func (wrapper *Wrapper) ChangeRowWidthTo(numberOfColumns uint) *Wrapper {
-	if numberOfColumns <= uint(len(wrapper.initialLineIndentString)) || numberOfColumns <= uint(len(wrapper.subsequentLinesIndentString)) {
+	if wrapper.fontMetrics != nil {
+		return wrapper.ChangeFontMetricsTo(wrapper.fontMetrics, float64(numberOfColumns))
+	}
+
+	if int(numberOfColumns) <= wrapper.widthOfRunes(wrapper.initialLineIndentString) || int(numberOfColumns) <= wrapper.widthOfRunes(wrapper.subsequentLinesIndentString) {
 		panic("RowWidth must be larger than row indent string")
 	}
 
@@ -65,7 +110,7 @@ func (wrapper *Wrapper) UsingRowWidth(numberOfColumns uint) *Wrapper {
 func (wrapper *Wrapper) ChangeIndentStringForFirstRowTo(indent string) *Wrapper {
 	wrapper.initialLineIndentString = []rune(indent)
 
-	if len(wrapper.initialLineIndentString) > int(wrapper.columnsPerRow) {
+	if wrapper.widthOfRunes(wrapper.initialLineIndentString) > int(wrapper.rowWidthInWidthUnits()) {
 		panic("RowWidth must be larger than row indent string")
 	}
 
@@ -83,7 +128,7 @@ func (wrapper *Wrapper) UsingIndentStringForFirstRow(indent string) *Wrapper {
 func (wrapper *Wrapper) ChangeIndentStringForRowsAfterTheFirstTo(indent string) *Wrapper {
 	wrapper.subsequentLinesIndentString = []rune(indent)
 
-	if len(wrapper.subsequentLinesIndentString) > int(wrapper.columnsPerRow) {
+	if wrapper.widthOfRunes(wrapper.subsequentLinesIndentString) > int(wrapper.rowWidthInWidthUnits()) {
 		panic("RowWidth must be larger than row indent string")
 	}
 
@@ -100,15 +145,41 @@ func (wrapper *Wrapper) UsingIndentStringForRowsAfterTheFirst(indent string) *Wr
 // treating incoming bytes as UTF-8 encoded text, wrapping using the rules described above. It will
 // Read() until it reaches io.EOF. It returns the wrapped text or an error if one occurs.
 func (wrapper *Wrapper) WrapUTF8TextFromAReader(reader io.Reader) (wrappedText string, err error) {
-	nibbler := nibblers.NewUTF8ReaderNibbler(reader)
-	return wrapper.wrapFromNibbler(nibbler)
+	wrappedText, _, err = wrapper.WrapUTF8TextFromAReaderAndReportTruncation(reader)
+	return wrappedText, err
+}
+
+// WrapUTF8TextFromAReaderAndReportTruncation is the same as WrapUTF8TextFromAReader(), but also reports
+// whether rows were dropped because the wrapped text had more rows than the maximum set by ChangeMaximumRowsTo().
+func (wrapper *Wrapper) WrapUTF8TextFromAReaderAndReportTruncation(reader io.Reader) (wrappedText string, contentWasDropped bool, err error) {
+	reader = wrapper.normalizingReaderFor(wrapper.inputLimitingReaderFor(reader))
+
+	if wrapper.detectIndentsFromInput {
+		return wrapper.wrapUsingIndentsDetectedFrom(reader)
+	}
+
+	return wrapper.wrapFromReaderAndTruncate(reader)
 }
 
 // WrapStringText takes a string and wraps it using the rules described above. It returns the wrapped
 // text or an error if one occurs.
 func (wrapper *Wrapper) WrapStringText(unwrappedString string) (wrappedText string, err error) {
-	nibbler := nibblers.NewUTF8StringNibbler(unwrappedString)
-	return wrapper.wrapFromNibbler(nibbler)
+	wrappedText, _, err = wrapper.WrapStringTextAndReportTruncation(unwrappedString)
+	return wrappedText, err
+}
+
+// WrapStringTextAndReportTruncation is the same as WrapStringText(), but also reports whether rows were
+// dropped because the wrapped text had more rows than the maximum set by ChangeMaximumRowsTo().
+func (wrapper *Wrapper) WrapStringTextAndReportTruncation(unwrappedString string) (wrappedText string, contentWasDropped bool, err error) {
+	if wrapper.detectIndentsFromInput || wrapper.inputNormalization != NoNormalization {
+		return wrapper.WrapUTF8TextFromAReaderAndReportTruncation(strings.NewReader(unwrappedString))
+	}
+
+	if wrapper.asciiPathIsAvailable() {
+		return wrapper.wrapBytesAndTruncate([]byte(unwrappedString))
+	}
+
+	return wrapper.wrapFromReaderAndTruncate(wrapper.inputLimitingReaderFor(strings.NewReader(unwrappedString)))
 }
 
 // MustWrapStringText is the same as WrapStringText but panics if an error occurs
@@ -137,175 +208,270 @@ type unwrappedTextProcessingState struct {
 	errorOrEOFCollectedFromChunkProcessing error
 }
 
-func wrappedTextStringOrEmptyStringBasedOnErrorOrEOF(err error, bufferOfWrappedText *bytes.Buffer) (string, error) {
-	if err == io.EOF {
-		return bufferOfWrappedText.String(), nil
-	}
-
+func (wrapper *Wrapper) wrapFromReader(reader io.Reader) (wrappedText string, err error) {
+	var bufferOfWrappedText bytes.Buffer
+	err = wrapper.wrapFromReaderInto(&bufferOfWrappedText, reader)
 	return bufferOfWrappedText.String(), err
 }
 
-func (wrapper *Wrapper) wrapFromNibbler(nibbler nibblers.UTF8Nibbler) (wrappedText string, err error) {
-	var bufferOfWrappedText bytes.Buffer
-
-	wrapper.nibblerMatcher = nibblers.NewUTF8NibblerMatcher(nibbler)
-	wrapper.nibbler = nibbler
+// wrapFromReaderInto appends the text wrapped from the UTF-8 text in reader to bufferOfWrappedText. If wrapping
+// fails, the text appended to bufferOfWrappedText ends with the last word that was written.
+func (wrapper *Wrapper) wrapFromReaderInto(bufferOfWrappedText *bytes.Buffer, reader io.Reader) (err error) {
+	buffers := wrapper.wrappingBuffersFor(reader)
+	defer buffers.release()
+
+	// if wrapping fails, the wrapped text ends with the last word that was written
+	positionAfterTheLastWrittenWord := buffers.nibbler.positionOfNextCharacter()
+	lengthOfWrappedTextAfterTheLastWrittenWord := bufferOfWrappedText.Len()
+	numberOfRowsAfterTheLastWrittenWord := 1
+	defer func() {
+		// a word truncated at the word length limit is the last word written, so the wrapped text is kept
+		if err == nil && buffers.nibbler.wordLengthLimitError != nil {
+			err = &WrapError{
+				Err:        buffers.nibbler.wordLengthLimitError,
+				ByteOffset: buffers.nibbler.positionOfTheTruncatedWord.byteOffset,
+				RuneOffset: buffers.nibbler.positionOfTheTruncatedWord.runeOffset,
+				Line:       buffers.nibbler.positionOfTheTruncatedWord.line,
+				Row:        buffers.numberOfWrappedRows,
+			}
+		} else if err != nil {
+			bufferOfWrappedText.Truncate(lengthOfWrappedTextAfterTheLastWrittenWord)
+			err = &WrapError{
+				Err:        err,
+				ByteOffset: positionAfterTheLastWrittenWord.byteOffset,
+				RuneOffset: positionAfterTheLastWrittenWord.runeOffset,
+				Line:       positionAfterTheLastWrittenWord.line,
+				Row:        numberOfRowsAfterTheLastWrittenWord,
+			}
+		}
+	}()
 
-	wordChunkBuffer := make([]rune, wrapper.columnsPerRow)
-	whitespaceChunkBuffer := make([]rune, wrapper.columnsPerRow)
+	wordChunkBuffer := buffers.wordChunkBufferFor(wrapper.runesInARow())
+	whitespaceChunkBuffer := buffers.whitespaceChunkBufferFor(wrapper.runesInARow())
 
-	if atEndOfStream, err := wrapper.afterRemovingContiguousWhitespace().reachedTheEndOfTheStream(); atEndOfStream {
-		return "", nil
+	if atEndOfStream, err := afterRemovingContiguousWhitespace(buffers).reachedTheEndOfTheStream(); atEndOfStream {
+		return nil
 	} else if err != nil {
-		return "", err
+		return err
 	}
 
-	if _, err := bufferOfWrappedText.WriteString(string(wrapper.initialLineIndentString)); err != nil {
-		return "", err
+	if err := writeRunesInto(bufferOfWrappedText, wrapper.initialLineIndentString); err != nil {
+		return err
 	}
 
-	columnsRemainingInCurrentWrappedLine := int(wrapper.columnsPerRow) - len(wrapper.initialLineIndentString)
+	columnsRemainingInCurrentWrappedLine := int(wrapper.rowWidthInWidthUnits()) - widthOfRunesUsing(buffers.advancer, wrapper.initialLineIndentString)
+	columnsInAWrappedLineAfterTheIndent := int(wrapper.rowWidthInWidthUnits()) - widthOfRunesUsing(buffers.advancer, wrapper.subsequentLinesIndentString)
+
+	previousRuneInLine := lastRuneOf(wrapper.initialLineIndentString)
+	previousRuneAfterTheIndent := lastRuneOf(wrapper.subsequentLinesIndentString)
 
-	numberOfRunesInLastWhitespaceChunk := 0
+	var whitespaceChunk []rune
 	atTheStartOfALine := true
+	breakIsAllowedBeforeNextWord := false
 
 	for {
-		wordRunesRead, err := wrapper.nibblerMatcher.ReadConsecutiveWordCharactersInto(wordChunkBuffer[:columnsRemainingInCurrentWrappedLine])
+		wordChunk, wordColumnsRead, wordIsCutByTheEndOfTheLine, err := wrapper.readWordCharactersThatFitIn(buffers, columnsRemainingInCurrentWrappedLine-widthOfRunesAfterUsing(buffers.advancer, previousRuneInLine, whitespaceChunk), wordChunkBuffer[:0], lastRuneOfEither(whitespaceChunk, previousRuneInLine), atTheStartOfALine)
 		if err == io.EOF {
-			return wrappedTextStringOrEmptyStringBasedOnErrorOrEOF(err, &bufferOfWrappedText)
+			return ignoringEOF(err)
+		} else if err != nil {
+			return err
 		}
 
-		if wordRunesRead == columnsRemainingInCurrentWrappedLine {
-			// if there were no whitespace chunks in this line, then this word is at least as long as an entire line
-			if numberOfRunesInLastWhitespaceChunk == 0 {
-				if _, err := bufferOfWrappedText.WriteString(string(wordChunkBuffer[:wordRunesRead])); err != nil {
-					return bufferOfWrappedText.String(), err
+		if wordIsCutByTheEndOfTheLine {
+			// if there was no whitespace or other break opportunity before this word in this line, then this word is at
+			// least as long as an entire line
+			if !breakIsAllowedBeforeNextWord {
+				if wordChunk, err = wrapper.afterApplyingKinsokuTo(buffers.nibbler, wordChunk); err != nil {
+					return err
 				}
 
-				if atEndOfStream, err := wrapper.afterRemovingContiguousWhitespace().reachedTheEndOfTheStream(); atEndOfStream {
-					return bufferOfWrappedText.String(), nil
-				} else if err != nil {
-					return bufferOfWrappedText.String(), err
+				if err := writeRunesInto(bufferOfWrappedText, wordChunk); err != nil {
+					return err
 				}
 
-				if wrapper.insertLineBreakAndIndentInto(&bufferOfWrappedText); err != nil {
-					return bufferOfWrappedText.String(), err
+				positionAfterTheLastWrittenWord = buffers.nibbler.positionOfNextCharacter()
+				lengthOfWrappedTextAfterTheLastWrittenWord = bufferOfWrappedText.Len()
+				numberOfRowsAfterTheLastWrittenWord = buffers.numberOfWrappedRows
+
+				if err := wrapper.insertLineBreakAndIndentInto(bufferOfWrappedText, &buffers.numberOfWrappedRows); err != nil {
+					return err
 				}
 
-				columnsRemainingInCurrentWrappedLine = int(wrapper.columnsPerRow) - len(wrapper.subsequentLinesIndentString)
+				columnsRemainingInCurrentWrappedLine = columnsInAWrappedLineAfterTheIndent
+				previousRuneInLine = previousRuneAfterTheIndent
 				atTheStartOfALine = true
+				breakIsAllowedBeforeNextWord = false
 			} else {
-				// if we are at the end of the stream, return
-				nextUnreadRune, err := wrapper.nibbler.PeekAtNextCharacter()
-				if err == io.EOF {
-					if _, writeErr := bufferOfWrappedText.WriteString(string(wordChunkBuffer[:wordRunesRead])); writeErr != nil {
-						return bufferOfWrappedText.String(), writeErr
-					}
-					return bufferOfWrappedText.String(), nil
-				} else if err != nil {
-					return bufferOfWrappedText.String(), err
+				// word buffer only has a fragment of a word but must wrap
+				if err := wrapper.insertLineBreakAndIndentInto(bufferOfWrappedText, &buffers.numberOfWrappedRows); err != nil {
+					return err
 				}
 
-				// if next character is a space, then we have a word that ends exactly at the line wrap length end
-				if unicode.IsSpace(nextUnreadRune) {
-					if numberOfRunesInLastWhitespaceChunk > 0 {
-						if _, err := bufferOfWrappedText.WriteString(changeAllWhitespaceToAnASCIISpace(whitespaceChunkBuffer[:numberOfRunesInLastWhitespaceChunk])); err != nil {
-							return bufferOfWrappedText.String(), err
-						}
-					}
-
-					if _, err := bufferOfWrappedText.WriteString(string(wordChunkBuffer[:wordRunesRead])); err != nil {
-						return bufferOfWrappedText.String(), err
-					}
-
-					if atEndOfStream, err := wrapper.afterRemovingContiguousWhitespace().reachedTheEndOfTheStream(); atEndOfStream {
-						return "", nil
-					} else if err != nil {
-						return "", err
-					}
-
-					if err := wrapper.insertLineBreakAndIndentInto(&bufferOfWrappedText); err != nil {
-						return bufferOfWrappedText.String(), err
-					}
-
-					columnsRemainingInCurrentWrappedLine = int(wrapper.columnsPerRow) - len(wrapper.subsequentLinesIndentString)
-					numberOfRunesInLastWhitespaceChunk = 0
-					atTheStartOfALine = true
-				} else {
-					// word buffer only has a fragment of a word but must wrap
-					if err := wrapper.insertLineBreakAndIndentInto(&bufferOfWrappedText); err != nil {
-						return bufferOfWrappedText.String(), err
-					}
-
-					if _, err := bufferOfWrappedText.WriteString(string(wordChunkBuffer[:wordRunesRead])); err != nil {
-						return bufferOfWrappedText.String(), err
-					}
-
-					columnsRemainingInCurrentWrappedLine = int(wrapper.columnsPerRow) - len(wrapper.subsequentLinesIndentString) - wordRunesRead
-					numberOfRunesInLastWhitespaceChunk = 0
-					atTheStartOfALine = false
+				if err := writeRunesInto(bufferOfWrappedText, wordChunk); err != nil {
+					return err
 				}
+
+				positionAfterTheLastWrittenWord = buffers.nibbler.positionOfNextCharacter()
+				lengthOfWrappedTextAfterTheLastWrittenWord = bufferOfWrappedText.Len()
+				numberOfRowsAfterTheLastWrittenWord = buffers.numberOfWrappedRows
+
+				columnsRemainingInCurrentWrappedLine = columnsInAWrappedLineAfterTheIndent - widthOfRunesAfterUsing(buffers.advancer, previousRuneAfterTheIndent, wordChunk)
+				previousRuneInLine = lastRuneOfEither(wordChunk, previousRuneAfterTheIndent)
+				whitespaceChunk = nil
+				atTheStartOfALine = len(wordChunk) == 0
+				breakIsAllowedBeforeNextWord = false
 			}
 		} else {
-			if numberOfRunesInLastWhitespaceChunk > 0 {
-				if _, err := bufferOfWrappedText.WriteString(changeAllWhitespaceToAnASCIISpace(whitespaceChunkBuffer[:numberOfRunesInLastWhitespaceChunk])); err != nil {
-					return bufferOfWrappedText.String(), err
+			if len(whitespaceChunk) > 0 {
+				if err := writeRunesInto(bufferOfWrappedText, whitespaceChunk); err != nil {
+					return err
 				}
+
+				columnsRemainingInCurrentWrappedLine -= widthOfRunesAfterUsing(buffers.advancer, previousRuneInLine, whitespaceChunk)
+				previousRuneInLine = lastRuneOf(whitespaceChunk)
 			}
 
-			if _, err := bufferOfWrappedText.WriteString(string(wordChunkBuffer[:wordRunesRead])); err != nil {
-				return bufferOfWrappedText.String(), err
+			if err := writeRunesInto(bufferOfWrappedText, wordChunk); err != nil {
+				return err
 			}
 
-			columnsRemainingInCurrentWrappedLine -= wordRunesRead
-			numberOfRunesInLastWhitespaceChunk = 0
+			positionAfterTheLastWrittenWord = buffers.nibbler.positionOfNextCharacter()
+			lengthOfWrappedTextAfterTheLastWrittenWord = bufferOfWrappedText.Len()
+			numberOfRowsAfterTheLastWrittenWord = buffers.numberOfWrappedRows
+
+			columnsRemainingInCurrentWrappedLine -= wordColumnsRead
+			previousRuneInLine = lastRuneOf(wordChunk)
+			whitespaceChunk = nil
 			atTheStartOfALine = false
+			breakIsAllowedBeforeNextWord = true
 		}
 
 		if !atTheStartOfALine {
-			whitespaceRunesRead, err := wrapper.nibblerMatcher.ReadConsecutiveWhitespaceInto(whitespaceChunkBuffer[:columnsRemainingInCurrentWrappedLine])
+			whitespaceChunk, err = wrapper.readWhitespaceCharactersThatFitIn(buffers, columnsRemainingInCurrentWrappedLine, whitespaceChunkBuffer[:0], previousRuneInLine)
 			if err != nil {
-				return wrappedTextStringOrEmptyStringBasedOnErrorOrEOF(err, &bufferOfWrappedText)
+				return ignoringEOF(err)
 			}
 
 			// whitespace continues to end of wrappable line, so wrap and don't write accumulated whitespace
-			if whitespaceRunesRead == columnsRemainingInCurrentWrappedLine {
-				if atEndOfStream, err := wrapper.afterRemovingContiguousWhitespace().reachedTheEndOfTheStream(); atEndOfStream {
-					return bufferOfWrappedText.String(), nil
+			if widthOfRunesAfterUsing(buffers.advancer, previousRuneInLine, whitespaceChunk) >= columnsRemainingInCurrentWrappedLine {
+				if atEndOfStream, err := afterRemovingContiguousWhitespace(buffers).reachedTheEndOfTheStream(); atEndOfStream {
+					return nil
 				} else if err != nil {
-					return bufferOfWrappedText.String(), err
+					return err
 				}
 
-				if err := wrapper.insertLineBreakAndIndentInto(&bufferOfWrappedText); err != nil {
-					return bufferOfWrappedText.String(), err
+				if err := wrapper.insertLineBreakAndIndentInto(bufferOfWrappedText, &buffers.numberOfWrappedRows); err != nil {
+					return err
 				}
 
-				columnsRemainingInCurrentWrappedLine = int(wrapper.columnsPerRow) - len(wrapper.subsequentLinesIndentString)
-				numberOfRunesInLastWhitespaceChunk = 0
+				columnsRemainingInCurrentWrappedLine = columnsInAWrappedLineAfterTheIndent
+				previousRuneInLine = previousRuneAfterTheIndent
+				whitespaceChunk = nil
 				atTheStartOfALine = true
-			} else {
-				columnsRemainingInCurrentWrappedLine -= whitespaceRunesRead
-				numberOfRunesInLastWhitespaceChunk = whitespaceRunesRead
-				atTheStartOfALine = false
+				breakIsAllowedBeforeNextWord = false
 			}
 		}
 	}
 }
 
-func changeAllWhitespaceToAnASCIISpace(whitespaceRunes []rune) string {
-	for i := range whitespaceRunes {
-		whitespaceRunes[i] = ' '
+// readWordCharactersThatFitIn reads consecutive non-whitespace runes from the nibbler, appending them to
+// wordChunk, until it reaches whitespace, the start of another word (as found by the WordSegmenter), the end
+// of the stream, or a rune that would make the word wider than columnsAvailable. In the last case,
+// wordIsCutByTheEndOfTheLine is true and the rune is left in the stream. The width of the first rune includes
+// any kerning with previousRune. A rune with zero width always fits, so it is never separated from the rune
+// before it. If atTheStartOfALine is true, the first rune is read even if it is wider than columnsAvailable,
+// since it would not fit on any following line either. Returns io.EOF only if the nibbler was already at the
+// end of the stream.
+func (wrapper *Wrapper) readWordCharactersThatFitIn(buffers *wrappingBuffers, columnsAvailable int, wordChunk []rune, previousRune rune, atTheStartOfALine bool) (word []rune, wordColumns int, wordIsCutByTheEndOfTheLine bool, err error) {
+	advancer := buffers.advancer
+	nibbler := buffers.nibbler
+
+	for {
+		nextRune, err := nibbler.ReadCharacter()
+		if err != nil {
+			if err == io.EOF && len(wordChunk) > 0 {
+				return wordChunk, wordColumns, false, nil
+			}
+
+			return wordChunk, wordColumns, false, err
+		}
+
+		if unicode.IsSpace(nextRune) || (len(wordChunk) > 0 && nibbler.lastReadCharacterStartsAWord()) {
+			return wordChunk, wordColumns, false, nibbler.UnreadCharacter()
+		}
+
+		widthOfNextRune := advancer.advanceOf(previousRune, nextRune)
+		if widthOfNextRune > 0 && wordColumns+widthOfNextRune > columnsAvailable && !(atTheStartOfALine && len(wordChunk) == 0) {
+			return wordChunk, wordColumns, true, nibbler.UnreadCharacter()
+		}
+
+		wordChunk = append(wordChunk, nextRune)
+		wordColumns += widthOfNextRune
+		previousRune = nextRune
 	}
+}
+
+// readWhitespaceCharactersThatFitIn reads consecutive whitespace runes from the nibbler, appending an ASCII space
+// to whitespaceChunk for each, until it reaches a non-whitespace rune, the end of the stream, or the whitespace is
+// at least columnsAvailable wide. In the last case, any whitespace that follows is left in the stream. Returns
+// io.EOF only if the nibbler was already at the end of the stream.
+func (wrapper *Wrapper) readWhitespaceCharactersThatFitIn(buffers *wrappingBuffers, columnsAvailable int, whitespaceChunk []rune, previousRune rune) (whitespace []rune, err error) {
+	advancer := buffers.advancer
+	nibbler := buffers.nibbler
+	whitespaceColumns := 0
+
+	for whitespaceColumns < columnsAvailable {
+		nextRune, err := nibbler.ReadCharacter()
+		if err != nil {
+			if err == io.EOF && len(whitespaceChunk) > 0 {
+				return whitespaceChunk, nil
+			}
+
+			return whitespaceChunk, err
+		}
 
-	return string(whitespaceRunes)
+		if !unicode.IsSpace(nextRune) {
+			return whitespaceChunk, nibbler.UnreadCharacter()
+		}
+
+		whitespaceColumns += advancer.advanceOf(previousRune, ' ')
+		whitespaceChunk = append(whitespaceChunk, ' ')
+		previousRune = ' '
+	}
+
+	return whitespaceChunk, nil
+}
+
+func lastRuneOf(runes []rune) rune {
+	if len(runes) == 0 {
+		return noPreviousRune
+	}
+
+	return runes[len(runes)-1]
 }
 
-func (wrapper *Wrapper) insertLineBreakAndIndentInto(bufferOfWrappedText *bytes.Buffer) error {
+func lastRuneOfEither(runes []rune, runeIfEmpty rune) rune {
+	if len(runes) == 0 {
+		return runeIfEmpty
+	}
+
+	return runes[len(runes)-1]
+}
+
+// insertLineBreakAndIndentInto writes a line break and the indent for the next row, and counts the new row in
+// numberOfWrappedRows.
+func (wrapper *Wrapper) insertLineBreakAndIndentInto(bufferOfWrappedText *bytes.Buffer, numberOfWrappedRows *int) error {
+	if err := wrapper.rowLimitError(*numberOfWrappedRows); err != nil {
+		return err
+	}
+
+	*numberOfWrappedRows++
+
 	if _, err := bufferOfWrappedText.WriteString(wrapper.lineBreakSequence); err != nil {
 		return err
 	}
 
-	if _, err := bufferOfWrappedText.WriteString(string(wrapper.subsequentLinesIndentString)); err != nil {
+	if err := writeRunesInto(bufferOfWrappedText, wrapper.subsequentLinesIndentString); err != nil {
 		return err
 	}
 
@@ -322,24 +488,24 @@ func processingHasReachedTheEndOfTheNibblerStreamFor(nibbler nibblers.UTF8Nibble
 
 type intercallState struct {
 	lastCallError error
-	wrapper       *Wrapper
+	nibbler       *breakOpportunityNibbler
 }
 
-func (wrapper *Wrapper) afterRemovingContiguousWhitespace() *intercallState {
-	if _, err := wrapper.nibblerMatcher.DiscardConsecutiveWhitespaceCharacters(); err != nil {
-		return &intercallState{
+func afterRemovingContiguousWhitespace(buffers *wrappingBuffers) intercallState {
+	if _, err := buffers.nibblerMatcher.DiscardConsecutiveWhitespaceCharacters(); err != nil {
+		return intercallState{
 			lastCallError: err,
-			wrapper:       wrapper,
+			nibbler:       buffers.nibbler,
 		}
 	}
 
-	return &intercallState{
+	return intercallState{
 		lastCallError: nil,
-		wrapper:       wrapper,
+		nibbler:       buffers.nibbler,
 	}
 }
 
-func (s *intercallState) reachedTheEndOfTheStream() (bool, error) {
+func (s intercallState) reachedTheEndOfTheStream() (bool, error) {
 	if s.lastCallError != nil {
 		if s.lastCallError == io.EOF {
 			return true, io.EOF
@@ -347,7 +513,7 @@ func (s *intercallState) reachedTheEndOfTheStream() (bool, error) {
 		return false, s.lastCallError
 	}
 
-	if _, err := s.wrapper.nibbler.PeekAtNextCharacter(); err == io.EOF {
+	if _, err := s.nibbler.PeekAtNextCharacter(); err == io.EOF {
 		return true, io.EOF
 	} else if err != nil {
 		return false, err
@@ -0,0 +1,8 @@
+//go:build !race
+// +build !race
+
+package text_test
+
+// raceDetectorIsEnabled is true when the tests are run with the race detector, which changes the number of
+// allocations (for example, sync.Pool drops some of the items put in it).
+const raceDetectorIsEnabled = false
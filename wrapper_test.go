@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/blorticus-go/text"
 )
@@ -235,6 +237,17 @@ func wrapTestSet(useReaderRatherThanString bool) (failedTests []error) {
 				"  lumnlength",
 			},
 		},
+		{
+			testName:         fmt.Sprintf("%s test 13", testNamePreamble),
+			unwrappedStrings: []string{"aaa bbbbbb", "aaa bbbbbb ", "aaa bbbbbb c"},
+			rowLength:        10,
+			useAReader:       useReaderRatherThanString,
+			expectedWrappedStrings: []string{
+				"aaa bbbbbb",
+				"aaa bbbbbb",
+				"aaa bbbbbb\nc",
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -261,3 +274,158 @@ func TestWrapUsingAReader(t *testing.T) {
 		t.Error(failedTestError.Error())
 	}
 }
+
+func TestWrapUsingAReaderThatReturnsDataWithEOF(t *testing.T) {
+	reader := iotest.DataErrReader(strings.NewReader("aaa bbbbbb c"))
+
+	wrappedString, err := text.NewWrapper().UsingRowWidth(10).WrapUTF8TextFromAReader(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if wrappedString != "aaa bbbbbb\nc" {
+		t.Errorf("expected = (%q), got = (%q)", "aaa bbbbbb\nc", wrappedString)
+	}
+}
+
+// wrapperBenchmark is one benchmark in the wrapper benchmark suite. The allocation target is checked by
+// TestWrapperBenchmarkAllocationTargets(). The time target is in nanoseconds per kilobyte (1024 bytes) of unwrapped
+// text, which each benchmark reports as "ns/KB". Times depend on the machine, so the time target is not checked.
+type wrapperBenchmark struct {
+	benchmarkName                string
+	wrapper                      *text.Wrapper
+	unwrappedText                string
+	useAppendWrapped             bool
+	targetAllocationsPerOp       float64
+	targetNanosecondsPerKilobyte float64
+}
+
+func (benchmark *wrapperBenchmark) wrapOnce(unwrappedBytes []byte, wrappedBytes []byte) ([]byte, error) {
+	if benchmark.useAppendWrapped {
+		return benchmark.wrapper.AppendWrapped(wrappedBytes[:0], unwrappedBytes)
+	}
+
+	wrappedText, err := benchmark.wrapper.WrapStringText(benchmark.unwrappedText)
+	return append(wrappedBytes[:0], wrappedText...), err
+}
+
+func wrapperBenchmarkSuite() []*wrapperBenchmark {
+	asciiText := strings.Repeat("The quick brown fox jumps over the lazy dog, and then it runs far away into the forest.\n", 12)
+	utf8Text := strings.Repeat("The quick brown fox jumps over the lazy dög, and then it runs far away into the forêt.\n", 12)
+	shortUTF8Message := "Ihre Bestellung wurde versandt und wird in Kürze zugestellt."
+
+	return []*wrapperBenchmark{
+		{
+			benchmarkName:                "AppendWrapped/ASCII",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60),
+			unwrappedText:                asciiText,
+			useAppendWrapped:             true,
+			targetAllocationsPerOp:       0,
+			targetNanosecondsPerKilobyte: 6000,
+		},
+		{
+			benchmarkName:                "AppendWrapped/ASCII with indents",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60).UsingIndentStringForFirstRow("  * ").UsingIndentStringForRowsAfterTheFirst("    "),
+			unwrappedText:                asciiText,
+			useAppendWrapped:             true,
+			targetAllocationsPerOp:       0,
+			targetNanosecondsPerKilobyte: 6000,
+		},
+		{
+			benchmarkName:                "AppendWrapped/ASCII without the ASCII path",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60).UsingMeasurer(oneColumnMeasurer),
+			unwrappedText:                asciiText,
+			useAppendWrapped:             true,
+			targetAllocationsPerOp:       1,
+			targetNanosecondsPerKilobyte: 75000,
+		},
+		{
+			benchmarkName:                "AppendWrapped/UTF-8",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60),
+			unwrappedText:                utf8Text,
+			useAppendWrapped:             true,
+			targetAllocationsPerOp:       1,
+			targetNanosecondsPerKilobyte: 75000,
+		},
+		{
+			benchmarkName:                "AppendWrapped/short UTF-8 message",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60),
+			unwrappedText:                shortUTF8Message,
+			useAppendWrapped:             true,
+			targetAllocationsPerOp:       1,
+			targetNanosecondsPerKilobyte: 100000,
+		},
+		{
+			benchmarkName:                "WrapStringText/ASCII",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60),
+			unwrappedText:                asciiText,
+			targetAllocationsPerOp:       3,
+			targetNanosecondsPerKilobyte: 7500,
+		},
+		{
+			benchmarkName:                "WrapStringText/UTF-8",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60),
+			unwrappedText:                utf8Text,
+			targetAllocationsPerOp:       4,
+			targetNanosecondsPerKilobyte: 75000,
+		},
+		{
+			benchmarkName:                "WrapStringText/short UTF-8 message",
+			wrapper:                      text.NewWrapper().UsingRowWidth(60),
+			unwrappedText:                shortUTF8Message,
+			targetAllocationsPerOp:       4,
+			targetNanosecondsPerKilobyte: 100000,
+		},
+	}
+}
+
+func BenchmarkWrapper(b *testing.B) {
+	for _, benchmark := range wrapperBenchmarkSuite() {
+		benchmark := benchmark
+
+		b.Run(benchmark.benchmarkName, func(b *testing.B) {
+			unwrappedBytes := []byte(benchmark.unwrappedText)
+			wrappedBytes := make([]byte, 0, 2*len(unwrappedBytes))
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(unwrappedBytes)))
+			b.ResetTimer()
+
+			startTime := time.Now()
+			for i := 0; i < b.N; i++ {
+				var err error
+				if wrappedBytes, err = benchmark.wrapOnce(unwrappedBytes, wrappedBytes); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			nanosecondsPerKilobyte := float64(time.Since(startTime).Nanoseconds()) / float64(b.N) / (float64(len(unwrappedBytes)) / 1024)
+			b.ReportMetric(nanosecondsPerKilobyte, "ns/KB")
+
+			// timings vary too much between machines to fail the benchmark when it misses its target, so the
+			// ratio to the target is reported instead (a value over 1 is a miss)
+			b.ReportMetric(nanosecondsPerKilobyte/benchmark.targetNanosecondsPerKilobyte, "ratio-to-target")
+		})
+	}
+}
+
+func TestWrapperBenchmarkAllocationTargets(t *testing.T) {
+	if raceDetectorIsEnabled {
+		t.Skip("allocations are not counted reliably with the race detector")
+	}
+
+	for _, benchmark := range wrapperBenchmarkSuite() {
+		unwrappedBytes := []byte(benchmark.unwrappedText)
+		wrappedBytes := make([]byte, 0, 2*len(unwrappedBytes))
+
+		allocationsPerOp := testing.AllocsPerRun(20, func() {
+			if _, err := benchmark.wrapOnce(unwrappedBytes, wrappedBytes); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		if allocationsPerOp > benchmark.targetAllocationsPerOp {
+			t.Errorf("[%s] expected at most %.0f allocations per op, got = %.0f", benchmark.benchmarkName, benchmark.targetAllocationsPerOp, allocationsPerOp)
+		}
+	}
+}
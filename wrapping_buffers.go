@@ -0,0 +1,98 @@
+package text
+
+import (
+	"io"
+	"sync"
+
+	"github.com/blorticus-go/nibblers"
+)
+
+// wrappingBuffers are the nibblers, buffers and rune advancers that the wrapping engine uses during one wrap
+// operation, and the number of rows it has wrapped. They are kept in poolOfWrappingBuffers between wrap operations,
+// so that a program that wraps many short texts (with one Wrapper or many) does not allocate them for each one.
+// Because this state is not kept in the Wrapper, one Wrapper can be used by many goroutines at once.
+type wrappingBuffers struct {
+	readerNibbler         *utf8ReaderNibbler
+	nibbler               *breakOpportunityNibbler
+	nibblerMatcher        *nibblers.UTF8NibblerMatcher
+	wordChunkBuffer       []rune
+	whitespaceChunkBuffer []rune
+	measurerAdvancer      measurerAdvancer
+	fontMetricsAdvancer   fontMetricsAdvancer
+	advancer              runeAdvancer
+	numberOfWrappedRows   int
+}
+
+var poolOfWrappingBuffers = sync.Pool{
+	New: func() interface{} {
+		readerNibbler := newUTF8ReaderNibbler(nil, FailOnInvalidUTF8)
+		nibbler := newBreakOpportunityNibbler(readerNibbler, nil, 0)
+
+		return &wrappingBuffers{
+			readerNibbler:         readerNibbler,
+			nibbler:               nibbler,
+			nibblerMatcher:        nibblers.NewUTF8NibblerMatcher(nibbler),
+			wordChunkBuffer:       nil,
+			whitespaceChunkBuffer: nil,
+			measurerAdvancer:      measurerAdvancer{},
+			fontMetricsAdvancer:   fontMetricsAdvancer{},
+			advancer:              nil,
+			numberOfWrappedRows:   0,
+		}
+	},
+}
+
+// wrappingBuffersFor takes wrappingBuffers from the pool, with a nibbler that reads UTF-8 text from reader using
+// the Wrapper's InvalidUTF8Policy, WordSegmenter and word length limit, the Wrapper's rune advancer, and one wrapped
+// row. They must be returned with release().
+func (wrapper *Wrapper) wrappingBuffersFor(reader io.Reader) *wrappingBuffers {
+	buffers := poolOfWrappingBuffers.Get().(*wrappingBuffers)
+	buffers.readerNibbler.reset(reader, wrapper.invalidUTF8Policy)
+	buffers.nibbler.reset(buffers.readerNibbler, wrapper.wordSegmenter, wrapper.wordLengthLimit)
+	buffers.advancer = buffers.advancerFor(wrapper)
+	buffers.numberOfWrappedRows = 1
+
+	return buffers
+}
+
+// release returns the buffers to the pool. The references to the reader, the WordSegmenter, the Measurer and the
+// FontMetrics are removed first, so that the pool does not keep them from being garbage collected.
+func (buffers *wrappingBuffers) release() {
+	buffers.readerNibbler.reset(nil, FailOnInvalidUTF8)
+	buffers.nibbler.reset(buffers.readerNibbler, nil, 0)
+	buffers.measurerAdvancer = measurerAdvancer{}
+	buffers.fontMetricsAdvancer = fontMetricsAdvancer{}
+	buffers.advancer = nil
+
+	poolOfWrappingBuffers.Put(buffers)
+}
+
+// advancerFor is the same as wrapper.advancer(), but the advancer is kept in the buffers, so it is not allocated.
+func (buffers *wrappingBuffers) advancerFor(wrapper *Wrapper) runeAdvancer {
+	if wrapper.fontMetrics != nil {
+		buffers.fontMetricsAdvancer.metrics = wrapper.fontMetrics
+		return &buffers.fontMetricsAdvancer
+	}
+
+	buffers.measurerAdvancer.measurer = wrapper.measurer
+	return &buffers.measurerAdvancer
+}
+
+// wordChunkBufferFor returns an empty buffer for the runes of a word, with room for at least runesInARow runes.
+func (buffers *wrappingBuffers) wordChunkBufferFor(runesInARow uint) []rune {
+	if uint(cap(buffers.wordChunkBuffer)) < runesInARow {
+		buffers.wordChunkBuffer = make([]rune, 0, runesInARow)
+	}
+
+	return buffers.wordChunkBuffer[:0]
+}
+
+// whitespaceChunkBufferFor returns an empty buffer for the runes of whitespace, with room for at least runesInARow
+// runes.
+func (buffers *wrappingBuffers) whitespaceChunkBufferFor(runesInARow uint) []rune {
+	if uint(cap(buffers.whitespaceChunkBuffer)) < runesInARow {
+		buffers.whitespaceChunkBuffer = make([]rune, 0, runesInARow)
+	}
+
+	return buffers.whitespaceChunkBuffer[:0]
+}
@@ -0,0 +1,182 @@
+package text_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/blorticus-go/text"
+)
+
+type ReusedBuffersTestCase struct {
+	testName              string
+	wrapper               *text.Wrapper
+	unwrappedString       string
+	expectedWrappedString string
+	expectError           bool
+}
+
+func (testCase *ReusedBuffersTestCase) RunTest() error {
+	wrappedString, err := testCase.wrapper.WrapStringText(testCase.unwrappedString)
+
+	if testCase.expectError && err == nil {
+		return fmt.Errorf("[%s] expected an error, got none", testCase.testName)
+	} else if !testCase.expectError && err != nil {
+		return fmt.Errorf("[%s] unexpected error: %s", testCase.testName, err)
+	}
+
+	if wrappedString != testCase.expectedWrappedString {
+		return fmt.Errorf("[%s] expected = (%q), got = (%q)", testCase.testName, testCase.expectedWrappedString, wrappedString)
+	}
+
+	return nil
+}
+
+// reusedBuffersTestSet has wrap operations whose configurations differ in each of the parts of the wrapping engine
+// that are reused between wrap operations, and operations that stop with an error part way through the input.
+func reusedBuffersTestSet() []*ReusedBuffersTestCase {
+	return []*ReusedBuffersTestCase{
+		{
+			testName:              "narrow rows",
+			wrapper:               text.NewWrapper().UsingRowWidth(6),
+			unwrappedString:       "the quick brown fox jumped",
+			expectedWrappedString: "the\nquick\nbrown\nfox\njumped",
+		},
+		{
+			testName:              "wide rows",
+			wrapper:               text.NewWrapper().UsingRowWidth(200),
+			unwrappedString:       "the quick brown fox jumped",
+			expectedWrappedString: "the quick brown fox jumped",
+		},
+		{
+			testName:              "invalid UTF-8 fails",
+			wrapper:               text.NewWrapper().UsingRowWidth(10),
+			unwrappedString:       "één twee \xff drie",
+			expectedWrappedString: "één twee",
+			expectError:           true,
+		},
+		{
+			testName:              "invalid UTF-8 replaced",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingInvalidUTF8Policy(text.ReplaceInvalidUTF8),
+			unwrappedString:       "één twee \xff drie",
+			expectedWrappedString: "één twee �\ndrie",
+		},
+		{
+			testName:              "word length limit",
+			wrapper:               text.NewWrapper().UsingRowWidth(10).UsingWordLengthLimit(4),
+			unwrappedString:       "één twee vier zeven",
+			expectedWrappedString: "één twee\nvier zeve",
+			expectError:           true,
+		},
+		{
+			testName:              "segmented words",
+			wrapper:               text.NewWrapper().UsingRowWidth(8).UsingWordSegmenter(text.NewDictionaryWordSegmenter()),
+			unwrappedString:       "ภาษาไทยง่ายนิดเดียว",
+			expectedWrappedString: "ภาษาไทย\nง่ายนิด\nเดียว",
+		},
+		{
+			testName:              "font metrics",
+			wrapper:               text.NewWrapper().UsingFontMetrics(text.NewFontMetricsTable(5), 40),
+			unwrappedString:       "één twee drie vier",
+			expectedWrappedString: "één twee\ndrie\nvier",
+		},
+	}
+}
+
+func TestWrapOperationsThatReuseBuffers(t *testing.T) {
+	testCases := reusedBuffersTestSet()
+
+	for pass := 0; pass < 3; pass++ {
+		for _, testCase := range testCases {
+			if err := testCase.RunTest(); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+func TestConcurrentWrapOperationsThatReuseBuffers(t *testing.T) {
+	var goroutinesThatHaveNotStopped sync.WaitGroup
+	errorsFromTestCases := make(chan error, 100)
+
+	for _, testCase := range reusedBuffersTestSet() {
+		testCase := testCase
+		goroutinesThatHaveNotStopped.Add(1)
+
+		go func() {
+			defer goroutinesThatHaveNotStopped.Done()
+
+			for repetition := 0; repetition < 50; repetition++ {
+				if err := testCase.RunTest(); err != nil {
+					errorsFromTestCases <- err
+					return
+				}
+			}
+		}()
+	}
+
+	goroutinesThatHaveNotStopped.Wait()
+	close(errorsFromTestCases)
+
+	for err := range errorsFromTestCases {
+		t.Error(err)
+	}
+}
+
+func TestWrapOperationsThatShareOneWrapperConcurrently(t *testing.T) {
+	sharedWrappers := []*text.Wrapper{
+		text.NewWrapper().UsingRowWidth(6),
+		text.NewWrapper().UsingRowWidth(10).UsingWordLengthLimit(4),
+		text.NewWrapper().UsingRowWidth(8).UsingWordSegmenter(text.NewDictionaryWordSegmenter()),
+		text.NewWrapper().UsingFontMetrics(text.NewFontMetricsTable(5), 40),
+		text.NewWrapper().UsingRowWidth(10).UsingOutputRowLimit(2),
+	}
+
+	unwrappedStrings := []string{
+		"the quick brown fox jumped",
+		"één twee vier zeven",
+		"ภาษาไทยง่ายนิดเดียว",
+		"one two three four five six",
+	}
+
+	for _, sharedWrapper := range sharedWrappers {
+		expectedResults := make([]string, len(unwrappedStrings))
+		expectedErrors := make([]bool, len(unwrappedStrings))
+		for i, unwrappedString := range unwrappedStrings {
+			wrappedString, err := sharedWrapper.WrapStringText(unwrappedString)
+			expectedResults[i], expectedErrors[i] = wrappedString, err != nil
+		}
+
+		var goroutinesThatHaveNotStopped sync.WaitGroup
+		errorsFromGoroutines := make(chan error, 8)
+
+		for goroutineNumber := 0; goroutineNumber < 8; goroutineNumber++ {
+			goroutineNumber := goroutineNumber
+			goroutinesThatHaveNotStopped.Add(1)
+
+			go func() {
+				defer goroutinesThatHaveNotStopped.Done()
+
+				for repetition := 0; repetition < 50; repetition++ {
+					i := (goroutineNumber + repetition) % len(unwrappedStrings)
+					wrappedString, err := sharedWrapper.WrapStringText(unwrappedStrings[i])
+
+					if (err != nil) != expectedErrors[i] {
+						errorsFromGoroutines <- fmt.Errorf("[goroutine %d, string %d] expected error = (%t), got = (%v)", goroutineNumber, i, expectedErrors[i], err)
+						return
+					} else if wrappedString != expectedResults[i] {
+						errorsFromGoroutines <- fmt.Errorf("[goroutine %d, string %d] expected = (%q), got = (%q)", goroutineNumber, i, expectedResults[i], wrappedString)
+						return
+					}
+				}
+			}()
+		}
+
+		goroutinesThatHaveNotStopped.Wait()
+		close(errorsFromGoroutines)
+
+		for err := range errorsFromGoroutines {
+			t.Error(err)
+		}
+	}
+}